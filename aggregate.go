@@ -0,0 +1,132 @@
+package dyno
+
+import (
+	"fmt"
+)
+
+// Sum returns the sum of the numbers denoted by the path inside each
+// element of slice.
+//
+// Values are converted using the rules of GetFloating. The error of the
+// first element whose value is missing or cannot be converted is reported
+// along with its index.
+//
+// If path is empty or nil, the elements themselves are summed.
+func Sum(slice []interface{}, path ...interface{}) (float64, error) {
+	var sum float64
+	for i, el := range slice {
+		f, err := GetFloating(el, path...)
+		if err != nil {
+			return 0, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+		sum += f
+	}
+	return sum, nil
+}
+
+// Avg returns the average of the numbers denoted by the path inside each
+// element of slice.
+//
+// See Sum for conversion rules. Slice cannot be empty, else an error is
+// returned.
+//
+// If path is empty or nil, the elements themselves are averaged.
+func Avg(slice []interface{}, path ...interface{}) (float64, error) {
+	if len(slice) == 0 {
+		return 0, fmt.Errorf("slice cannot be empty")
+	}
+	sum, err := Sum(slice, path...)
+	if err != nil {
+		return 0, err
+	}
+	return sum / float64(len(slice)), nil
+}
+
+// MinBy returns the element of slice having the smallest number denoted by
+// the path inside the element. If more elements have the same smallest
+// value, the first one is returned.
+//
+// See Sum for conversion rules. Slice cannot be empty, else an error is
+// returned.
+//
+// If path is empty or nil, the elements themselves are compared.
+func MinBy(slice []interface{}, path ...interface{}) (interface{}, error) {
+	return extremeBy(slice, path, func(f, best float64) bool { return f < best })
+}
+
+// MaxBy returns the element of slice having the largest number denoted by
+// the path inside the element. If more elements have the same largest
+// value, the first one is returned.
+//
+// See Sum for conversion rules. Slice cannot be empty, else an error is
+// returned.
+//
+// If path is empty or nil, the elements themselves are compared.
+func MaxBy(slice []interface{}, path ...interface{}) (interface{}, error) {
+	return extremeBy(slice, path, func(f, best float64) bool { return f > best })
+}
+
+// extremeBy returns the element of slice whose value denoted by path is
+// better than the values of all other elements.
+func extremeBy(slice []interface{}, path []interface{}, better func(f, best float64) bool) (interface{}, error) {
+	if len(slice) == 0 {
+		return nil, fmt.Errorf("slice cannot be empty")
+	}
+
+	var res interface{}
+	var best float64
+	for i, el := range slice {
+		f, err := GetFloating(el, path...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+		if i == 0 || better(f, best) {
+			res, best = el, f
+		}
+	}
+	return res, nil
+}
+
+// CountBy counts the elements of slice grouped by the value denoted by
+// keyPath inside each element. The result maps the keys to the counts (of
+// type int).
+//
+// Keys are rendered using the rules of GetText. The error of the first
+// element whose key is missing or cannot be rendered is reported along with
+// its index.
+//
+// If keyPath is empty or nil, the elements themselves are used as keys.
+func CountBy(slice []interface{}, keyPath ...interface{}) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	for i, el := range slice {
+		key, err := GetText(el, keyPath...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+		n, _ := res[key].(int)
+		res[key] = n + 1
+	}
+	return res, nil
+}
+
+// GroupBy groups the elements of slice by the value denoted by keyPath
+// inside each element. The result maps the keys to the slices of elements
+// (of type []interface{}), preserving the order of the elements.
+//
+// Keys are rendered using the rules of GetText. The error of the first
+// element whose key is missing or cannot be rendered is reported along with
+// its index.
+//
+// If keyPath is empty or nil, the elements themselves are used as keys.
+func GroupBy(slice []interface{}, keyPath ...interface{}) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	for i, el := range slice {
+		key, err := GetText(el, keyPath...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+		group, _ := res[key].([]interface{})
+		res[key] = append(group, el)
+	}
+	return res, nil
+}
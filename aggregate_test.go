@@ -0,0 +1,122 @@
+package dyno
+
+import (
+	"reflect"
+	"testing"
+)
+
+var records = []interface{}{
+	map[string]interface{}{"team": "a", "stats": map[string]interface{}{"score": 3}},
+	map[string]interface{}{"team": "b", "stats": map[string]interface{}{"score": 9.5}},
+	map[interface{}]interface{}{"team": "a", "stats": map[string]interface{}{"score": "1.5"}},
+}
+
+func TestSumAvg(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		slice []interface{} // Input slice
+		path  []interface{} // path of the field inside elements
+		sum   float64       // Expected sum
+		avg   float64       // Expected average
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "nested field",
+			slice: records,
+			path:  []interface{}{"stats", "score"},
+			sum:   14,
+			avg:   14.0 / 3,
+		},
+		{
+			title: "elements themselves",
+			slice: []interface{}{1, 2.5, uint8(3)},
+			sum:   6.5,
+			avg:   6.5 / 3,
+		},
+
+		// Test errors:
+		{
+			title: "missing field error",
+			slice: records,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid value error",
+			slice: records,
+			path:  []interface{}{"team"},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		sum, err := Sum(c.slice, c.path...)
+		if sum != c.sum {
+			t.Errorf("[title: %s] Expected sum: %v, got: %v", c.title, c.sum, sum)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+		avg, err := Avg(c.slice, c.path...)
+		if avg != c.avg {
+			t.Errorf("[title: %s] Expected avg: %v, got: %v", c.title, c.avg, avg)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	if _, err := Avg(nil); err == nil {
+		t.Errorf("Expected error for empty slice")
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	min, err := MinBy(records, "stats", "score")
+	if !reflect.DeepEqual(min, records[2]) || err != nil {
+		t.Errorf("Expected min: %v, got: %v, err value: %v", records[2], min, err)
+	}
+	max, err := MaxBy(records, "stats", "score")
+	if !reflect.DeepEqual(max, records[1]) || err != nil {
+		t.Errorf("Expected max: %v, got: %v, err value: %v", records[1], max, err)
+	}
+	first, err := MaxBy([]interface{}{1, 3, 3.0}, nil...)
+	if first != 3 || err != nil {
+		t.Errorf("Expected max: 3, got: %v, err value: %v", first, err)
+	}
+
+	if _, err := MinBy(nil); err == nil {
+		t.Errorf("Expected error for empty slice")
+	}
+	if _, err := MaxBy(records, "team"); err == nil {
+		t.Errorf("Expected error for invalid value")
+	}
+}
+
+func TestCountByGroupBy(t *testing.T) {
+	counts, err := CountBy(records, "team")
+	if exp := map[string]interface{}{"a": 2, "b": 1}; !reflect.DeepEqual(counts, exp) || err != nil {
+		t.Errorf("Expected counts: %v, got: %v, err value: %v", exp, counts, err)
+	}
+	counts, err = CountBy([]interface{}{1, 1.0, true})
+	if exp := map[string]interface{}{"1": 2, "true": 1}; !reflect.DeepEqual(counts, exp) || err != nil {
+		t.Errorf("Expected counts: %v, got: %v, err value: %v", exp, counts, err)
+	}
+
+	groups, err := GroupBy(records, "team")
+	exp := map[string]interface{}{
+		"a": []interface{}{records[0], records[2]},
+		"b": []interface{}{records[1]},
+	}
+	if !reflect.DeepEqual(groups, exp) || err != nil {
+		t.Errorf("Expected groups: %v, got: %v, err value: %v", exp, groups, err)
+	}
+
+	if _, err := CountBy(records, "stats"); err == nil {
+		t.Errorf("Expected error for non-scalar key")
+	}
+	if _, err := GroupBy(records, "x"); err == nil {
+		t.Errorf("Expected error for missing key")
+	}
+}
@@ -0,0 +1,338 @@
+package dyno
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxInt = int(^uint(0) >> 1)
+	minInt = -maxInt - 1
+)
+
+// Increment adds delta to the number denoted by the path.
+//
+// The result is stored back with the same type as the original value,
+// e.g. an int stays int, a float64 stays float64, and a json.Number stays
+// json.Number. Supported types of the original value are the integer and
+// floating point types, json.Number, *big.Int and *big.Float. Arithmetic
+// on json.Number and math/big values is exact.
+//
+// delta may be of any type accepted by GetInteger or GetFloating, but it
+// must be an integer if the original value is of an integer type.
+// If the result does not fit into the original type, an error is returned
+// and the original value is left unchanged.
+//
+// If the map key denoted by the path does not exist, delta is stored.
+//
+// Path cannot be empty or nil, else an error is returned.
+func Increment(v interface{}, delta interface{}, path ...interface{}) error {
+	return updateNumber(v, delta, path, func(cur interface{}, exists bool) (interface{}, bool, error) {
+		if !exists {
+			return delta, true, nil
+		}
+		res, err := calcNumber(cur, delta, false)
+		return res, err == nil, err
+	})
+}
+
+// Multiply multiplies the number denoted by the path by factor.
+//
+// The result is stored back with the same type as the original value.
+// See Increment for the supported types and overflow detection.
+//
+// If the map key denoted by the path does not exist, a zero value of the
+// type of factor is stored.
+//
+// Path cannot be empty or nil, else an error is returned.
+func Multiply(v interface{}, factor interface{}, path ...interface{}) error {
+	return updateNumber(v, factor, path, func(cur interface{}, exists bool) (interface{}, bool, error) {
+		if !exists {
+			res, err := ratToKind(factor, new(big.Rat))
+			return res, err == nil, err
+		}
+		res, err := calcNumber(cur, factor, true)
+		return res, err == nil, err
+	})
+}
+
+// Min updates the number denoted by the path to limit if limit is less
+// than the current value, so the value is clamped to be at most limit.
+//
+// The result is stored with the same type as the original value.
+// See Increment for the supported types.
+//
+// If the map key denoted by the path does not exist, limit is stored.
+//
+// Path cannot be empty or nil, else an error is returned.
+func Min(v interface{}, limit interface{}, path ...interface{}) error {
+	return updateNumber(v, limit, path, func(cur interface{}, exists bool) (interface{}, bool, error) {
+		return clampNumber(cur, exists, limit, 1)
+	})
+}
+
+// Max updates the number denoted by the path to limit if limit is greater
+// than the current value, so the value is clamped to be at least limit.
+//
+// The result is stored with the same type as the original value.
+// See Increment for the supported types.
+//
+// If the map key denoted by the path does not exist, limit is stored.
+//
+// Path cannot be empty or nil, else an error is returned.
+func Max(v interface{}, limit interface{}, path ...interface{}) error {
+	return updateNumber(v, limit, path, func(cur interface{}, exists bool) (interface{}, bool, error) {
+		return clampNumber(cur, exists, limit, -1)
+	})
+}
+
+// updateNumber resolves the parent of the value denoted by the path, and
+// calls f with the current value. If f reports the value must be changed,
+// its result is set.
+func updateNumber(v, operand interface{}, path []interface{},
+	f func(cur interface{}, exists bool) (res interface{}, change bool, err error)) error {
+
+	node, err := getParent(v, path)
+	if err != nil {
+		return err
+	}
+
+	i := len(path) - 1 // The last index
+	cur, exists, err := getElem(node, path[i], i)
+	if err != nil {
+		return err
+	}
+	if !exists && !isNumber(operand) {
+		return fmt.Errorf("missing key: %v (path element idx: %d)", path[i], i)
+	}
+
+	res, change, err := f(cur, exists)
+	if err != nil || !change {
+		return err
+	}
+	return setElem(node, path[i], i, res)
+}
+
+// clampNumber returns limit converted to the type of cur if cur compared
+// to limit is dir (1 means cur is greater than limit, so it is to be
+// lowered; -1 means cur is less than limit, so it is to be raised).
+func clampNumber(cur interface{}, exists bool, limit interface{}, dir int) (interface{}, bool, error) {
+	if !exists {
+		return limit, true, nil
+	}
+
+	r, err := decimalRat(limit)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isNumber(cur) {
+		return nil, false, fmt.Errorf("expected some form of number, got: %T", cur)
+	}
+	cr, err := decimalRat(cur)
+	if err != nil {
+		return nil, false, err
+	}
+	if cr.Cmp(r) != dir {
+		return nil, false, nil
+	}
+	res, err := ratToKind(cur, r)
+	return res, err == nil, err
+}
+
+// calcNumber returns cur+operand (or cur*operand if mul is true), having the
+// same type as cur.
+func calcNumber(cur, operand interface{}, mul bool) (interface{}, error) {
+	r, err := decimalRat(operand)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := cur.(type) {
+	case float64, float32:
+		f, _ := GetFloating(c)
+		rf, _ := r.Float64()
+		if mul {
+			f *= rf
+		} else {
+			f += rf
+		}
+		return floatToKind(cur, f)
+	}
+
+	cr, ok := toRat(cur)
+	if !ok {
+		return nil, fmt.Errorf("expected some form of number, got: %T", cur)
+	}
+	res := new(big.Rat)
+	if mul {
+		res.Mul(cr, r)
+	} else {
+		res.Add(cr, r)
+	}
+	return ratToKind(cur, res)
+}
+
+// decimalRat converts the number x to *big.Rat. Floating point numbers are
+// converted using their shortest decimal representation (e.g. 0.1 is
+// converted to 1/10 and not to its exact binary value).
+// Strings holding a number are also accepted.
+func decimalRat(x interface{}) (*big.Rat, error) {
+	var s string
+	switch n := x.(type) {
+	case float64:
+		s = strconv.FormatFloat(n, 'g', -1, 64)
+	case float32:
+		s = strconv.FormatFloat(float64(n), 'g', -1, 32)
+	case string:
+		s = strings.TrimSpace(n)
+	default:
+		if r, ok := toRat(x); ok {
+			return new(big.Rat).Set(r), nil
+		}
+		return nil, fmt.Errorf("expected some form of number, got: %T", x)
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid number: %q", s)
+	}
+	return r, nil
+}
+
+// floatToKind converts f to the floating point type of kind.
+func floatToKind(kind interface{}, f float64) (interface{}, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("%T overflow: %v", kind, f)
+	}
+	if _, ok := kind.(float32); ok {
+		if math.Abs(f) > math.MaxFloat32 {
+			return nil, fmt.Errorf("%T overflow: %v", kind, f)
+		}
+		return float32(f), nil
+	}
+	return f, nil
+}
+
+// ratToKind converts r to a value having the same type as kind.
+// An error is returned if r cannot be represented by that type.
+func ratToKind(kind interface{}, r *big.Rat) (interface{}, error) {
+	switch k := kind.(type) {
+	case float64, float32:
+		f, _ := r.Float64()
+		return floatToKind(kind, f)
+	case json.Number:
+		return json.Number(ratToDecimal(r)), nil
+	case *big.Float:
+		prec := k.Prec()
+		if prec < 64 {
+			prec = 64
+		}
+		return new(big.Float).SetPrec(prec).SetRat(r), nil
+	}
+
+	if !r.IsInt() {
+		return nil, fmt.Errorf("cannot represent %s as %T", r.RatString(), kind)
+	}
+	n := r.Num()
+
+	overflow := func() (interface{}, error) {
+		return nil, fmt.Errorf("%T overflow: %s", kind, n)
+	}
+
+	switch kind.(type) {
+	case *big.Int:
+		return new(big.Int).Set(n), nil
+	case uint, uint64, uint32, uint16, uint8:
+		if n.Sign() < 0 || !n.IsUint64() {
+			return overflow()
+		}
+		u := n.Uint64()
+		switch kind.(type) {
+		case uint:
+			if uint64(uint(u)) != u {
+				return overflow()
+			}
+			return uint(u), nil
+		case uint64:
+			return u, nil
+		case uint32:
+			if u > math.MaxUint32 {
+				return overflow()
+			}
+			return uint32(u), nil
+		case uint16:
+			if u > math.MaxUint16 {
+				return overflow()
+			}
+			return uint16(u), nil
+		default:
+			if u > math.MaxUint8 {
+				return overflow()
+			}
+			return uint8(u), nil
+		}
+	case int, int64, int32, int16, int8:
+		if !n.IsInt64() {
+			return overflow()
+		}
+		i := n.Int64()
+		switch kind.(type) {
+		case int:
+			if i < int64(minInt) || i > int64(maxInt) {
+				return overflow()
+			}
+			return int(i), nil
+		case int64:
+			return i, nil
+		case int32:
+			if i < math.MinInt32 || i > math.MaxInt32 {
+				return overflow()
+			}
+			return int32(i), nil
+		case int16:
+			if i < math.MinInt16 || i > math.MaxInt16 {
+				return overflow()
+			}
+			return int16(i), nil
+		default:
+			if i < math.MinInt8 || i > math.MaxInt8 {
+				return overflow()
+			}
+			return int8(i), nil
+		}
+	}
+
+	return nil, fmt.Errorf("expected some form of number, got: %T", kind)
+}
+
+// ratToDecimal returns the exact decimal representation of r if it has
+// one, else r is rounded to 34 decimal digits.
+func ratToDecimal(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	d := new(big.Int).Set(r.Denom())
+	two, five, zero := big.NewInt(2), big.NewInt(5), new(big.Int)
+	mod := new(big.Int)
+	twos, fives := 0, 0
+	for mod.Mod(d, two).Cmp(zero) == 0 {
+		d.Quo(d, two)
+		twos++
+	}
+	for mod.Mod(d, five).Cmp(zero) == 0 {
+		d.Quo(d, five)
+		fives++
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return r.FloatString(34)
+	}
+	if twos > fives {
+		return r.FloatString(twos)
+	}
+	return r.FloatString(fives)
+}
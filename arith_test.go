@@ -0,0 +1,350 @@
+package dyno
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestIncrement(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		delta interface{}   // Delta to add
+		path  []interface{} // path whose value to increment
+		exp   interface{}   // Expected result
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "int stays int",
+			v:     map[string]interface{}{"n": 1},
+			delta: 2.0,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 3},
+		},
+		{
+			title: "float64 stays float64",
+			v:     map[string]interface{}{"n": 1.5},
+			delta: 1,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 2.5},
+		},
+		{
+			title: "float32 stays float32",
+			v:     []interface{}{float32(1.5)},
+			delta: "0.25",
+			path:  []interface{}{0},
+			exp:   []interface{}{float32(1.75)},
+		},
+		{
+			title: "uint8 stays uint8",
+			v:     map[interface{}]interface{}{1: uint8(250)},
+			delta: 5,
+			path:  []interface{}{1},
+			exp:   map[interface{}]interface{}{1: uint8(255)},
+		},
+		{
+			title: "json.Number stays json.Number and exact",
+			v:     map[string]interface{}{"n": json.Number("0.1")},
+			delta: 0.2,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": json.Number("0.3")},
+		},
+		{
+			title: "huge json.Number",
+			v:     map[string]interface{}{"n": json.Number("99999999999999999999")},
+			delta: 1,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": json.Number("100000000000000000000")},
+		},
+		{
+			title: "missing key",
+			v:     map[string]interface{}{},
+			delta: 3,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 3},
+		},
+
+		// Test errors:
+		{
+			title: "path cannot be empty error",
+			v:     1,
+			delta: 1,
+			exp:   1,
+			isErr: true,
+		},
+		{
+			title: "int8 overflow error",
+			v:     map[string]interface{}{"n": int8(127)},
+			delta: 1,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": int8(127)},
+			isErr: true,
+		},
+		{
+			title: "uint underflow error",
+			v:     map[string]interface{}{"n": uint(0)},
+			delta: -1,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": uint(0)},
+			isErr: true,
+		},
+		{
+			title: "int64 overflow error",
+			v:     map[string]interface{}{"n": int64(math.MaxInt64)},
+			delta: 1,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": int64(math.MaxInt64)},
+			isErr: true,
+		},
+		{
+			title: "float64 overflow error",
+			v:     map[string]interface{}{"n": math.MaxFloat64},
+			delta: math.MaxFloat64,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": math.MaxFloat64},
+			isErr: true,
+		},
+		{
+			title: "non-integer delta for int error",
+			v:     map[string]interface{}{"n": 1},
+			delta: 0.5,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 1},
+			isErr: true,
+		},
+		{
+			title: "non-numeric value error",
+			v:     map[string]interface{}{"n": "1"},
+			delta: 1,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": "1"},
+			isErr: true,
+		},
+		{
+			title: "invalid delta error",
+			v:     map[string]interface{}{"n": 1},
+			delta: "x",
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 1},
+			isErr: true,
+		},
+		{
+			title: "missing key with non-numeric delta error",
+			v:     map[string]interface{}{},
+			delta: "1",
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+		{
+			title: "index out of range error",
+			v:     []interface{}{1},
+			delta: 1,
+			path:  []interface{}{1},
+			exp:   []interface{}{1},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		err := Increment(c.v, c.delta, c.path...)
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestIncrementBig(t *testing.T) {
+	m := map[string]interface{}{
+		"i": new(big.Int).Lsh(big.NewInt(1), 100),
+		"f": big.NewFloat(1.5),
+	}
+	if err := Increment(m, 1, "i"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if exp := "1267650600228229401496703205377"; m["i"].(*big.Int).String() != exp {
+		t.Errorf("Expected value: %v, got: %v", exp, m["i"])
+	}
+	if err := Increment(m, json.Number("0.25"), "f"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if exp := "1.75"; m["f"].(*big.Float).Text('g', -1) != exp {
+		t.Errorf("Expected value: %v, got: %v", exp, m["f"])
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	cases := []struct {
+		title  string        // Title of the test case
+		v      interface{}   // Input dynamic object
+		factor interface{}   // Factor to multiply with
+		path   []interface{} // path whose value to multiply
+		exp    interface{}   // Expected result
+		isErr  bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title:  "int stays int",
+			v:      map[string]interface{}{"n": 3},
+			factor: 4,
+			path:   []interface{}{"n"},
+			exp:    map[string]interface{}{"n": 12},
+		},
+		{
+			title:  "int times fraction giving integer",
+			v:      map[string]interface{}{"n": 4},
+			factor: "1.5",
+			path:   []interface{}{"n"},
+			exp:    map[string]interface{}{"n": 6},
+		},
+		{
+			title:  "float64 stays float64",
+			v:      map[string]interface{}{"n": 1.5},
+			factor: 3,
+			path:   []interface{}{"n"},
+			exp:    map[string]interface{}{"n": 4.5},
+		},
+		{
+			title:  "json.Number stays json.Number",
+			v:      map[string]interface{}{"n": json.Number("1.1")},
+			factor: json.Number("1.1"),
+			path:   []interface{}{"n"},
+			exp:    map[string]interface{}{"n": json.Number("1.21")},
+		},
+		{
+			title:  "missing key",
+			v:      map[string]interface{}{},
+			factor: 2.5,
+			path:   []interface{}{"n"},
+			exp:    map[string]interface{}{"n": 0.0},
+		},
+
+		// Test errors:
+		{
+			title:  "int times fraction giving non-integer error",
+			v:      map[string]interface{}{"n": 3},
+			factor: "1.5",
+			path:   []interface{}{"n"},
+			exp:    map[string]interface{}{"n": 3},
+			isErr:  true,
+		},
+		{
+			title:  "int16 overflow error",
+			v:      map[string]interface{}{"n": int16(300)},
+			factor: 300,
+			path:   []interface{}{"n"},
+			exp:    map[string]interface{}{"n": int16(300)},
+			isErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		err := Multiply(c.v, c.factor, c.path...)
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		max   bool          // Tells if Max is to be tested (else Min)
+		v     interface{}   // Input dynamic object
+		limit interface{}   // Limit
+		path  []interface{} // path whose value to clamp
+		exp   interface{}   // Expected result
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "min lowers int",
+			v:     map[string]interface{}{"n": 10},
+			limit: 5.0,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 5},
+		},
+		{
+			title: "min keeps lower value",
+			v:     map[string]interface{}{"n": 3},
+			limit: 5,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 3},
+		},
+		{
+			title: "min keeps equal float",
+			v:     map[string]interface{}{"n": 0.1},
+			limit: json.Number("0.1"),
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 0.1},
+		},
+		{
+			title: "max raises json.Number",
+			max:   true,
+			v:     map[string]interface{}{"n": json.Number("1")},
+			limit: 2.5,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": json.Number("2.5")},
+		},
+		{
+			title: "max keeps greater value",
+			max:   true,
+			v:     map[string]interface{}{"n": uint(7)},
+			limit: 2,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": uint(7)},
+		},
+		{
+			title: "max on missing key",
+			max:   true,
+			v:     map[string]interface{}{},
+			limit: 2,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": 2},
+		},
+
+		// Test errors:
+		{
+			title: "max overflow error",
+			max:   true,
+			v:     map[string]interface{}{"n": int8(1)},
+			limit: 1000,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": int8(1)},
+			isErr: true,
+		},
+		{
+			title: "non-numeric value error",
+			v:     map[string]interface{}{"n": true},
+			limit: 1,
+			path:  []interface{}{"n"},
+			exp:   map[string]interface{}{"n": true},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		var err error
+		if c.max {
+			err = Max(c.v, c.limit, c.path...)
+		} else {
+			err = Min(c.v, c.limit, c.path...)
+		}
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
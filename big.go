@@ -0,0 +1,321 @@
+package dyno
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// GetBigInt returns a *big.Int value denoted by the path.
+//
+// This function accepts many different types and converts them to *big.Int
+// without going through float64 (so no precision is lost), namely:
+//   -*big.Int (a copy is returned), *big.Float (truncated)
+//   -integer types (int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64)
+//   -floating point types (float64, float32) (truncated)
+//   -string and json.Number (decimal integers of any size, or decimal
+//    floating point numbers which are truncated)
+//
+// If path is empty or nil, v is returned as a *big.Int.
+func GetBigInt(v interface{}, path ...interface{}) (*big.Int, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch i := v.(type) {
+	case *big.Int:
+		return new(big.Int).Set(i), nil
+	case string:
+		return parseBigInt(i)
+	case json.Number:
+		return parseBigInt(string(i))
+	}
+
+	if u, ok := v.(uint64); ok {
+		return new(big.Int).SetUint64(u), nil
+	}
+	if u, ok := v.(uint); ok {
+		return new(big.Int).SetUint64(uint64(u)), nil
+	}
+
+	f, err := GetBigFloat(v)
+	if err != nil {
+		return nil, fmt.Errorf("expected some form of integer number, got: %T", v)
+	}
+	if f.IsInf() {
+		return nil, fmt.Errorf("cannot convert infinity to integer")
+	}
+	n, _ := f.Int(nil)
+	return n, nil
+}
+
+// parseBigInt parses s as a decimal integer. If s is not an integer but a
+// floating point number, it is truncated.
+func parseBigInt(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return n, nil
+	}
+	f, err := parseBigFloat(s)
+	if err != nil {
+		return nil, err
+	}
+	n, _ := f.Int(nil)
+	return n, nil
+}
+
+// GetBigFloat returns a *big.Float value denoted by the path.
+//
+// This function accepts many different types and converts them to *big.Float
+// without going through float64 (so no precision is lost), namely:
+//   -*big.Float (a copy is returned), *big.Int
+//   -integer types (int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64)
+//   -floating point types (float64, float32)
+//   -string and json.Number (the precision of the result is chosen so that
+//    all digits of the input are preserved)
+//
+// NaN values are reported as errors as *big.Float cannot represent them.
+//
+// If path is empty or nil, v is returned as a *big.Float.
+func GetBigFloat(v interface{}, path ...interface{}) (*big.Float, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f := v.(type) {
+	case *big.Float:
+		return new(big.Float).Copy(f), nil
+	case *big.Int:
+		return new(big.Float).SetPrec(uint(max64(int64(f.BitLen()), 64))).SetInt(f), nil
+	case string:
+		return parseBigFloat(f)
+	case json.Number:
+		return parseBigFloat(string(f))
+	case float64:
+		if math.IsNaN(f) {
+			return nil, fmt.Errorf("cannot convert NaN to big.Float")
+		}
+		return big.NewFloat(f), nil
+	case float32:
+		if math.IsNaN(float64(f)) {
+			return nil, fmt.Errorf("cannot convert NaN to big.Float")
+		}
+		return big.NewFloat(float64(f)), nil
+	case uint64:
+		return new(big.Float).SetUint64(f), nil
+	case uint:
+		return new(big.Float).SetUint64(uint64(f)), nil
+	case int64, int, int32, int16, int8, uint32, uint16, uint8:
+		n, err := GetInteger(f)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Float).SetInt64(n), nil
+	default:
+		return nil, fmt.Errorf("expected some form of floating point number, got: %T", v)
+	}
+}
+
+// parseBigFloat parses s as a decimal floating point number, using a
+// precision that preserves all its digits.
+func parseBigFloat(s string) (*big.Float, error) {
+	s = strings.TrimSpace(s)
+	// Each decimal digit needs less than 4 bits:
+	prec := uint(max64(int64(len(s))*4, 64))
+	f, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number: %q", s)
+	}
+	return f, nil
+}
+
+// GetNumber returns a json.Number value denoted by the path.
+//
+// This function accepts many different types and converts them to
+// json.Number without going through float64 (so no precision is lost), namely:
+//   -json.Number
+//   -string (must hold a valid JSON number)
+//   -integer types (int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64)
+//   -floating point types (float64, float32) (formatted with the shortest
+//    representation that round-trips)
+//   -*big.Int, *big.Float
+//
+// If path is empty or nil, v is returned as a json.Number.
+func GetNumber(v interface{}, path ...interface{}) (json.Number, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return "", err
+	}
+
+	switch n := v.(type) {
+	case json.Number:
+		return n, nil
+	case string:
+		s := strings.TrimSpace(n)
+		if !isJSONNumber(s) {
+			return "", fmt.Errorf("invalid JSON number: %q", s)
+		}
+		return json.Number(s), nil
+	case *big.Int:
+		return json.Number(n.String()), nil
+	case *big.Float:
+		if n.IsInf() {
+			return "", fmt.Errorf("cannot convert infinity to number")
+		}
+		return json.Number(n.Text('g', -1)), nil
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", fmt.Errorf("cannot convert %v to number", n)
+		}
+		return json.Number(formatFloat(n, 64)), nil
+	case float32:
+		if math.IsNaN(float64(n)) || math.IsInf(float64(n), 0) {
+			return "", fmt.Errorf("cannot convert %v to number", n)
+		}
+		return json.Number(formatFloat(float64(n), 32)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(n, 10)), nil
+	case uint:
+		return json.Number(strconv.FormatUint(uint64(n), 10)), nil
+	case int64, int, int32, int16, int8, uint32, uint16, uint8:
+		i, err := GetInteger(n)
+		if err != nil {
+			return "", err
+		}
+		return json.Number(strconv.FormatInt(i, 10)), nil
+	default:
+		return "", fmt.Errorf("expected some form of number, got: %T", v)
+	}
+}
+
+// isJSONNumber tells if s is a number by the JSON grammar.
+func isJSONNumber(s string) bool {
+	return s != "" && (s[0] == '-' || isDigit(s[0])) && json.Valid([]byte(s))
+}
+
+// toRat converts the numeric value v to an exact *big.Rat.
+// The second return value tells if v is a (finite) number.
+// Strings are not considered numbers, but json.Number is.
+func toRat(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case uint:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(n))), true
+	case uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(n)), true
+	case uint32:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case uint16:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case uint8:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case float64:
+		r := new(big.Rat).SetFloat64(n)
+		return r, r != nil
+	case float32:
+		r := new(big.Rat).SetFloat64(float64(n))
+		return r, r != nil
+	case json.Number:
+		return new(big.Rat).SetString(strings.TrimSpace(string(n)))
+	case *big.Int:
+		return new(big.Rat).SetInt(n), true
+	case *big.Float:
+		if n.IsInf() {
+			return nil, false
+		}
+		r, _ := n.Rat(nil)
+		return r, true
+	case *big.Rat:
+		return n, true
+	}
+	return nil, false
+}
+
+// isNumber tells if v is of a numeric type (including json.Number and
+// math/big types).
+func isNumber(v interface{}) bool {
+	switch v.(type) {
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8,
+		float64, float32, json.Number, *big.Int, *big.Float, *big.Rat:
+		return true
+	}
+	return false
+}
+
+// compareNumbers compares the numeric values a and b without losing
+// precision (see compareRat). Result is -1 if a < b, 0 if a == b and +1 if
+// a > b. The second return value tells if the comparison succeeded (both a
+// and b are comparable numbers).
+func compareNumbers(a, b interface{}) (int, bool) {
+	// Fast path for the most common case:
+	if fa, ok := a.(float64); ok {
+		if fb, ok := b.(float64); ok {
+			switch {
+			case fa < fb:
+				return -1, true
+			case fa > fb:
+				return 1, true
+			case fa == fb:
+				return 0, true
+			}
+			return 0, false // NaN
+		}
+	}
+
+	ra, ok := compareRat(a, b)
+	if !ok {
+		return 0, false
+	}
+	rb, ok := compareRat(b, a)
+	if !ok {
+		return 0, false
+	}
+	return ra.Cmp(rb), true
+}
+
+// compareRat converts the number v to *big.Rat for comparing it with other.
+// If v is a non-integer floating point number and other is a json.Number
+// or a math/big value, v is converted using its shortest decimal
+// representation (so 0.1 equals json.Number("0.1"), see decimalRat), else
+// exactly (so integers beyond 2^53 keep their values).
+func compareRat(v, other interface{}) (*big.Rat, bool) {
+	switch other.(type) {
+	case json.Number, *big.Int, *big.Float, *big.Rat:
+		var f float64
+		switch n := v.(type) {
+		case float64:
+			f = n
+		case float32:
+			f = float64(n)
+		default:
+			return toRat(v)
+		}
+		if f != math.Trunc(f) { // Also true for NaN
+			r, err := decimalRat(v)
+			return r, err == nil
+		}
+	}
+	return toRat(v)
+}
+
+// max64 returns the larger of a and b.
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,264 @@
+package dyno
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestGetBigInt(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value string        // Expected value (in decimal)
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from huge json.Number",
+			v:     map[string]interface{}{"n": json.Number("123456789012345678901234567890")},
+			path:  []interface{}{"n"},
+			value: "123456789012345678901234567890",
+		},
+		{
+			title: "success from string",
+			v:     "-98765432109876543210",
+			value: "-98765432109876543210",
+		},
+		{
+			title: "success from fractional string",
+			v:     "12.9",
+			value: "12",
+		},
+		{
+			title: "success from int",
+			v:     ms,
+			path:  []interface{}{"a"},
+			value: "1",
+		},
+		{
+			title: "success from uint64",
+			v:     uint64(math.MaxUint64),
+			value: "18446744073709551615",
+		},
+		{
+			title: "success from float64",
+			v:     1e20,
+			value: "100000000000000000000",
+		},
+		{
+			title: "success from *big.Int",
+			v:     big.NewInt(42),
+			value: "42",
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid string error",
+			v:     "abc",
+			isErr: true,
+		},
+		{
+			title: "infinity error",
+			v:     math.Inf(1),
+			isErr: true,
+		},
+		{
+			title: "expected some form of integer error",
+			v:     true,
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetBigInt(c.v, c.path...)
+		if value != nil && value.String() != c.value || value == nil && c.value != "" {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	// Result must be a copy:
+	orig := big.NewInt(1)
+	n, _ := GetBigInt(orig)
+	n.SetInt64(2)
+	if orig.Int64() != 1 {
+		t.Errorf("Source was modified: %v", orig)
+	}
+}
+
+func TestGetBigFloat(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value string        // Expected value (formatted with %.30g)
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from precise json.Number",
+			v:     map[string]interface{}{"n": json.Number("0.1234567890123456789012345")},
+			path:  []interface{}{"n"},
+			value: "0.1234567890123456789012345",
+		},
+		{
+			title: "success from float64",
+			v:     0.5,
+			value: "0.5",
+		},
+		{
+			title: "success from uint64",
+			v:     uint64(math.MaxUint64),
+			value: "18446744073709551615",
+		},
+		{
+			title: "success from int8",
+			v:     int8(-3),
+			value: "-3",
+		},
+		{
+			title: "success from *big.Int",
+			v:     new(big.Int).Lsh(big.NewInt(1), 100),
+			value: "1267650600228229401496703205376",
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid string error",
+			v:     "1.2.3",
+			isErr: true,
+		},
+		{
+			title: "NaN error",
+			v:     math.NaN(),
+			isErr: true,
+		},
+		{
+			title: "expected some form of floating point number error",
+			v:     []interface{}{},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetBigFloat(c.v, c.path...)
+		if value != nil && value.Text('g', 31) != c.value || value == nil && c.value != "" {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestGetNumber(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value json.Number   // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from json.Number",
+			v:     map[string]interface{}{"n": json.Number("1.000000000000000000001")},
+			path:  []interface{}{"n"},
+			value: "1.000000000000000000001",
+		},
+		{
+			title: "success from string",
+			v:     "12345678901234567890",
+			value: "12345678901234567890",
+		},
+		{
+			title: "success from int",
+			v:     ms,
+			path:  []interface{}{"a"},
+			value: "1",
+		},
+		{
+			title: "success from float64",
+			v:     ms,
+			path:  []interface{}{"flt"},
+			value: "3.14",
+		},
+		{
+			title: "success from integral float64",
+			v:     1e6,
+			value: "1000000",
+		},
+		{
+			title: "success from *big.Int",
+			v:     big.NewInt(-7),
+			value: "-7",
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid string error",
+			v:     "x1",
+			isErr: true,
+		},
+		{
+			title: "non-JSON number string error",
+			v:     "Inf",
+			isErr: true,
+		},
+		{
+			title: "NaN error",
+			v:     math.NaN(),
+			isErr: true,
+		},
+		{
+			title: "expected some form of number error",
+			v:     true,
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetNumber(c.v, c.path...)
+		if value != c.value {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestIsJSONNumber(t *testing.T) {
+	for s, exp := range map[string]bool{
+		"0": true, "-1.5e10": true, "12345678901234567890": true,
+		"": false, "Inf": false, "-Inf": false, "NaN": false, "0x10": false, "+1": false,
+		".5": false, "1.": false, "01": false, "1_000": false, `"1"`: false, "1 2": false,
+	} {
+		if got := isJSONNumber(s); got != exp {
+			t.Errorf("[input: %q] Expected: %v, got: %v", s, exp, got)
+		}
+	}
+}
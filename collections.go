@@ -0,0 +1,135 @@
+package dyno
+
+import (
+	"fmt"
+)
+
+// GetStrings returns a []string denoted by the path.
+//
+// The value denoted by the path must be a slice, and each of its elements
+// is converted using the rules of GetString. The error of the first
+// element that cannot be converted is reported along with its index.
+//
+// If path is empty or nil, v is returned as a []string.
+func GetStrings(v interface{}, path ...interface{}) ([]string, error) {
+	s, err := GetSlice(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, len(s))
+	for i, el := range s {
+		if res[i], err = GetString(el); err != nil {
+			return nil, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+	}
+	return res, nil
+}
+
+// GetIntegers returns a []int64 denoted by the path.
+//
+// The value denoted by the path must be a slice, and each of its elements
+// is converted using the rules of GetInteger. The error of the first
+// element that cannot be converted is reported along with its index.
+//
+// If path is empty or nil, v is returned as a []int64.
+func GetIntegers(v interface{}, path ...interface{}) ([]int64, error) {
+	s, err := GetSlice(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]int64, len(s))
+	for i, el := range s {
+		if res[i], err = GetInteger(el); err != nil {
+			return nil, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+	}
+	return res, nil
+}
+
+// GetFloatings returns a []float64 denoted by the path.
+//
+// The value denoted by the path must be a slice, and each of its elements
+// is converted using the rules of GetFloating. The error of the first
+// element that cannot be converted is reported along with its index.
+//
+// If path is empty or nil, v is returned as a []float64.
+func GetFloatings(v interface{}, path ...interface{}) ([]float64, error) {
+	s, err := GetSlice(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]float64, len(s))
+	for i, el := range s {
+		if res[i], err = GetFloating(el); err != nil {
+			return nil, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+	}
+	return res, nil
+}
+
+// GetBooleans returns a []bool denoted by the path.
+//
+// The value denoted by the path must be a slice, and each of its elements
+// is converted using the rules of GetBoolean. The error of the first
+// element that cannot be converted is reported along with its index.
+//
+// If path is empty or nil, v is returned as a []bool.
+func GetBooleans(v interface{}, path ...interface{}) ([]bool, error) {
+	s, err := GetSlice(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]bool, len(s))
+	for i, el := range s {
+		if res[i], err = GetBoolean(el); err != nil {
+			return nil, fmt.Errorf("invalid element at index %d: %v", i, err)
+		}
+	}
+	return res, nil
+}
+
+// GetStringMap returns a map[string]string denoted by the path.
+//
+// The value denoted by the path must be a map with string keys or a map
+// with interface{} keys. In case of the latter, fmt.Sprint() with default
+// formatting is used to convert the keys to string keys (just like
+// ConvertMapI2MapS does). Values are converted using the rules of GetString.
+// The error of the first value that cannot be converted is reported along
+// with its key.
+//
+// If path is empty or nil, v is returned as a map[string]string.
+func GetStringMap(v interface{}, path ...interface{}) (map[string]string, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]string, len(m))
+		for k, el := range m {
+			if res[k], err = GetString(el); err != nil {
+				return nil, fmt.Errorf("invalid value for key %s: %v", k, err)
+			}
+		}
+		return res, nil
+
+	case map[interface{}]interface{}:
+		res := make(map[string]string, len(m))
+		for k, el := range m {
+			s, err := GetString(el)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for key %v: %v", k, err)
+			}
+			res[fmt.Sprint(k)] = s
+		}
+		return res, nil
+
+	default:
+		return nil, fmt.Errorf("expected map node, got: %T", v)
+	}
+}
@@ -0,0 +1,224 @@
+package dyno
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGetStrings(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value []string      // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success",
+			v:     map[string]interface{}{"a": []interface{}{"x", "y"}},
+			path:  []interface{}{"a"},
+			value: []string{"x", "y"},
+		},
+		{
+			title: "empty slice",
+			v:     []interface{}{},
+			value: []string{},
+		},
+
+		// Test errors:
+		{
+			title: "internal GetSlice call returns error",
+			v:     ms,
+			path:  []interface{}{"a"},
+			isErr: true,
+		},
+		{
+			title: "invalid element error",
+			v:     []interface{}{"x", 1},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetStrings(c.v, c.path...)
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestGetIntegers(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value []int64       // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from mixed elements",
+			v:     map[string]interface{}{"a": []interface{}{1, int8(2), 3.0, "4", json.Number("5")}},
+			path:  []interface{}{"a"},
+			value: []int64{1, 2, 3, 4, 5},
+		},
+
+		// Test errors:
+		{
+			title: "internal GetSlice call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid element error",
+			v:     []interface{}{1, true},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetIntegers(c.v, c.path...)
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestGetFloatings(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value []float64     // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from mixed elements",
+			v:     ms,
+			path:  []interface{}{"ns"},
+			value: []float64{1.1, 2.2, 3.3},
+		},
+		{
+			title: "success from mixed elements #2",
+			v:     []interface{}{1, float32(0.5), "2.5"},
+			value: []float64{1, 0.5, 2.5},
+		},
+
+		// Test errors:
+		{
+			title: "invalid element error",
+			v:     []interface{}{1.1, []interface{}{}},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetFloatings(c.v, c.path...)
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestGetBooleans(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value []bool        // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from mixed elements",
+			v:     []interface{}{true, 0, 1.5, "false"},
+			value: []bool{true, false, true, false},
+		},
+
+		// Test errors:
+		{
+			title: "invalid element error",
+			v:     []interface{}{true, []interface{}{}},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetBooleans(c.v, c.path...)
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestGetStringMap(t *testing.T) {
+	cases := []struct {
+		title string            // Title of the test case
+		v     interface{}       // Input dynamic object
+		path  []interface{}     // path whose value to get
+		value map[string]string // Expected value
+		isErr bool              // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from ms",
+			v:     map[string]interface{}{"labels": map[string]interface{}{"a": "x", "b": "y"}},
+			path:  []interface{}{"labels"},
+			value: map[string]string{"a": "x", "b": "y"},
+		},
+		{
+			title: "success from mi",
+			v:     map[interface{}]interface{}{"a": "x", 1: "y"},
+			value: map[string]string{"a": "x", "1": "y"},
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid value (ms) error",
+			v:     map[string]interface{}{"a": 1},
+			isErr: true,
+		},
+		{
+			title: "invalid value (mi) error",
+			v:     map[interface{}]interface{}{"a": 1},
+			isErr: true,
+		},
+		{
+			title: "expected map node error",
+			v:     []interface{}{},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetStringMap(c.v, c.path...)
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
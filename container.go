@@ -0,0 +1,98 @@
+package dyno
+
+import (
+	"fmt"
+)
+
+// Container is the interface custom node types may implement to participate
+// in all operations of this package, just like the built-in node types
+// map[string]interface{}, map[interface{}]interface{} and []interface{}.
+//
+// Containers are consulted when a node is not one of the built-in node
+// types (and before the reflection fallback, see ReflectFallback). Keys are
+// the path elements denoting elements of the container: map keys for
+// map-like containers, int indices for list-like containers.
+type Container interface {
+	// GetElem returns the element denoted by key, and whether it exists.
+	// A missing key should be reported by returning exists=false; an
+	// error should only be returned for invalid keys (e.g. a key of the
+	// wrong type or an index out of range).
+	GetElem(key interface{}) (value interface{}, exists bool, err error)
+
+	// SetElem sets the element denoted by key.
+	SetElem(key, value interface{}) error
+
+	// DeleteElem deletes the element denoted by key. List-like containers
+	// should shift subsequent elements.
+	DeleteElem(key interface{}) error
+
+	// Len returns the number of elements.
+	Len() int
+
+	// Range calls f for each element in the container's order, until f
+	// returns false. The container must not be modified by f.
+	Range(f func(key, value interface{}) bool)
+}
+
+// Cloner is an optional interface of Containers, needed to deep copy them
+// (e.g. by ApplyUpdate and Omit). Containers not implementing it are not
+// copied, the copy shares them with the original.
+type Cloner interface {
+	// Clone returns a shallow copy of the container: a new container of
+	// the same kind having the same elements.
+	Clone() Container
+}
+
+// containerGetElem is the Container fallback of getElem.
+func containerGetElem(c Container, el interface{}, i int) (value interface{}, exists bool, err error) {
+	value, exists, err = c.GetElem(el)
+	if err != nil {
+		err = fmt.Errorf("%v (path element idx: %d)", err, i)
+	}
+	return
+}
+
+// containerSetElem is the Container fallback of setElem.
+func containerSetElem(c Container, el interface{}, i int, value interface{}) error {
+	if err := c.SetElem(el, value); err != nil {
+		return fmt.Errorf("%v (path element idx: %d)", err, i)
+	}
+	return nil
+}
+
+// containerKeys returns the keys of c in the container's order.
+func containerKeys(c Container) []interface{} {
+	keys := make([]interface{}, 0, c.Len())
+	c.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// containerChildren calls f for each element of c, in the container's order.
+// Elements are collected first, so f may modify c.
+func containerChildren(c Container, f func(key, child interface{}) error) error {
+	var keys, values []interface{}
+	c.Range(func(key, value interface{}) bool {
+		keys, values = append(keys, key), append(values, value)
+		return true
+	})
+	for i, key := range keys {
+		if err := f(key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteElems deletes the elements of c denoted by keys, in reverse order,
+// so deleting ascending indices of list-like containers works as expected.
+func deleteElems(c Container, keys []interface{}) error {
+	for i := len(keys) - 1; i >= 0; i-- {
+		if err := c.DeleteElem(keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
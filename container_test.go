@@ -0,0 +1,187 @@
+package dyno
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// testList is a list-like Container for testing.
+type testList struct {
+	elems []interface{}
+}
+
+func (l *testList) GetElem(key interface{}) (interface{}, bool, error) {
+	idx, ok := key.(int)
+	if !ok {
+		return nil, false, fmt.Errorf("expected int key, got: %T", key)
+	}
+	if idx < 0 || idx >= len(l.elems) {
+		return nil, false, nil
+	}
+	return l.elems[idx], true, nil
+}
+
+func (l *testList) SetElem(key, value interface{}) error {
+	idx, ok := key.(int)
+	if !ok {
+		return fmt.Errorf("expected int key, got: %T", key)
+	}
+	switch {
+	case idx >= 0 && idx < len(l.elems):
+		l.elems[idx] = value
+	case idx == len(l.elems):
+		l.elems = append(l.elems, value)
+	default:
+		return fmt.Errorf("index out of range: %d", idx)
+	}
+	return nil
+}
+
+func (l *testList) DeleteElem(key interface{}) error {
+	idx, ok := key.(int)
+	if !ok {
+		return fmt.Errorf("expected int key, got: %T", key)
+	}
+	if idx < 0 || idx >= len(l.elems) {
+		return fmt.Errorf("index out of range: %d", idx)
+	}
+	l.elems = append(l.elems[:idx], l.elems[idx+1:]...)
+	return nil
+}
+
+func (l *testList) Len() int { return len(l.elems) }
+
+func (l *testList) Range(f func(key, value interface{}) bool) {
+	for i, v := range l.elems {
+		if !f(i, v) {
+			return
+		}
+	}
+}
+
+func (l *testList) Clone() Container {
+	return &testList{elems: append([]interface{}(nil), l.elems...)}
+}
+
+// newTestList returns a testList holding the given elements.
+func newTestList(elems ...interface{}) *testList {
+	return &testList{elems: elems}
+}
+
+func TestContainerGetSet(t *testing.T) {
+	v := map[string]interface{}{
+		"l": newTestList("a", map[string]interface{}{"b": 1}),
+	}
+
+	cases := []struct {
+		title string        // Title of the test case
+		path  []interface{} // Input path
+		value interface{}   // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{"element", []interface{}{"l", 0}, "a", false},
+		{"nested", []interface{}{"l", 1, "b"}, 1, false},
+
+		// Test errors:
+		{"missing key error", []interface{}{"l", 2}, nil, true},
+		{"invalid key error", []interface{}{"l", "x"}, nil, true},
+	}
+
+	for _, c := range cases {
+		value, err := Get(v, c.path...)
+		if value != c.value {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	if err := Set(v, "x", "l", 0); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := Set(v, 2, "l", 1, "b"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := Set(v, 3, "l", 5); err == nil {
+		t.Errorf("Expected error for index out of range")
+	}
+	exp := []interface{}{"x", map[string]interface{}{"b": 2}}
+	if got := v["l"].(*testList).elems; !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected elements: %v, got: %v", exp, got)
+	}
+}
+
+func TestContainerAppendDelete(t *testing.T) {
+	l := newTestList("a")
+	v := map[string]interface{}{"l": l}
+
+	if err := Append(v, "b", "l"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := AppendMore(v, []interface{}{"c", "d"}, "l"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := Delete(v, 1, "l"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := Delete(v, 9, "l"); err == nil {
+		t.Errorf("Expected error for index out of range")
+	}
+	if exp := []interface{}{"a", "c", "d"}; !reflect.DeepEqual(l.elems, exp) {
+		t.Errorf("Expected elements: %v, got: %v", exp, l.elems)
+	}
+}
+
+func TestContainerConvertMapI2MapS(t *testing.T) {
+	l := newTestList(map[interface{}]interface{}{"a": 1})
+	ConvertMapI2MapS(l)
+	if exp := []interface{}{map[string]interface{}{"a": 1}}; !reflect.DeepEqual(l.elems, exp) {
+		t.Errorf("Expected elements: %v, got: %v", exp, l.elems)
+	}
+}
+
+func TestContainerWalkers(t *testing.T) {
+	v := map[string]interface{}{
+		"l": newTestList(
+			map[string]interface{}{"id": 1},
+			map[string]interface{}{"id": 2},
+			map[string]interface{}{"id": 3},
+		),
+	}
+
+	values, paths := GetAll(v, "l", Any, "id")
+	if exp := []interface{}{1, 2, 3}; !reflect.DeepEqual(values, exp) {
+		t.Errorf("Expected values: %v, got: %v", exp, values)
+	}
+	if exp := [][]interface{}{{"l", 0, "id"}, {"l", 1, "id"}, {"l", 2, "id"}}; !reflect.DeepEqual(paths, exp) {
+		t.Errorf("Expected paths: %v, got: %v", exp, paths)
+	}
+
+	if got := FindKey(v, "id", 0); len(got) != 3 {
+		t.Errorf("Expected 3 paths, got: %v", got)
+	}
+
+	flat, err := Flatten(v, nil)
+	if exp := map[string]interface{}{"l.0.id": 1, "l.1.id": 2, "l.2.id": 3}; err != nil || !reflect.DeepEqual(flat, exp) {
+		t.Errorf("Expected flat: %v, got: %v, err value: %v", exp, flat, err)
+	}
+
+	// Omit works on a clone, v must not change:
+	omitted := Omit(v, []interface{}{"l", 1})
+	if got := omitted.(map[string]interface{})["l"].(*testList).Len(); got != 2 {
+		t.Errorf("Expected length: %d, got: %d", 2, got)
+	}
+	if got := v["l"].(*testList).Len(); got != 3 {
+		t.Errorf("Expected original length: %d, got: %d", 3, got)
+	}
+
+	if _, _, err := DeleteAll(v, "l", Any); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if got := v["l"].(*testList).Len(); got != 0 {
+		t.Errorf("Expected length: %d, got: %d", 0, got)
+	}
+}
@@ -0,0 +1,39 @@
+package dyno
+
+// deepCopy returns a deep copy of the dynamic object v.
+//
+// Maps with string and interface{} key types, slices and Containers
+// implementing Cloner are copied recursively, other values are copied as-is.
+func deepCopy(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v2 := range x {
+			m[k] = deepCopy(v2)
+		}
+		return m
+
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(x))
+		for k, v2 := range x {
+			m[k] = deepCopy(v2)
+		}
+		return m
+
+	case []interface{}:
+		s := make([]interface{}, len(x))
+		for i, v2 := range x {
+			s[i] = deepCopy(v2)
+		}
+		return s
+
+	case Cloner:
+		c := x.Clone()
+		containerChildren(c, func(key, child interface{}) error {
+			return c.SetElem(key, deepCopy(child))
+		})
+		return c
+	}
+
+	return v
+}
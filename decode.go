@@ -0,0 +1,436 @@
+package dyno
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DecodeOpts holds options for DecodeWith.
+type DecodeOpts struct {
+	// TagName is the struct tag key naming struct fields (e.g. "yaml" or
+	// "mapstructure"). If empty, json tag names are used, falling back to
+	// yaml tag names (like when navigating into structs, see
+	// ReflectFallback).
+	TagName string
+
+	// ErrorUnknown tells if map keys having no corresponding struct field
+	// are errors. If false, such keys are ignored.
+	ErrorUnknown bool
+
+	// Unknown, if not nil, is called with the concrete path of each map key
+	// having no corresponding struct field.
+	Unknown func(path []interface{})
+
+	// DurationUnit is the unit of numbers decoded into time.Duration values
+	// (see GetDurationUnit). If zero, time.Nanosecond is used.
+	DurationUnit time.Duration
+
+	// TimeOpts are the options used to decode time.Time values (see
+	// GetTimeOpts). May be nil.
+	TimeOpts *TimeOpts
+}
+
+// DecodeError is the error returned by Decode and DecodeWith if a value
+// cannot be decoded, it tells the path of the value.
+type DecodeError struct {
+	Path []interface{} // Concrete path of the value in the dynamic object
+	Err  error         // The underlying error
+}
+
+// Error returns the error message.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode error at path %v: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decode decodes the value denoted by the path into out using the default
+// options. See DecodeWith for details.
+//
+// If path is empty or nil, v itself is decoded.
+func Decode(v interface{}, out interface{}, path ...interface{}) error {
+	return DecodeWith(v, out, nil, path...)
+}
+
+// DecodeWith decodes the value denoted by the path into out, which must be
+// a non-nil pointer (e.g. to a struct, a slice or a map). It is a faster
+// alternative to marshaling to JSON and unmarshaling, which also supports
+// maps with interface{} keys.
+//
+// Values are decoded according to the type of the target, namely:
+//   -struct from maps (of both kinds, and Containers): keys are matched
+//    against field names (see DecodeOpts.TagName) exactly, then
+//    case-insensitively; fields of embedded structs are promoted; fields
+//    having no key keep their values
+//   -map from maps, keys are decoded like values (e.g. "1" into an int key)
+//   -slice and array from slices (and Containers); a string into []byte
+//   -pointer: allocated if nil, the pointed value is decoded
+//   -types implementing json.Unmarshaler: the value is marshaled to JSON
+//    and passed to UnmarshalJSON
+//   -types implementing encoding.TextUnmarshaler from strings
+//   -time.Duration using the rules of GetDurationUnit
+//   -time.Time using the rules of GetTimeOpts
+//   -bool, integer and floating point types using the rules of GetBoolean,
+//    GetInteger and GetFloating (e.g. "12" into an int), with range check
+//   -string using the rules of GetText (e.g. 12 into "12")
+// Values assignable to the target type are stored as-is (they are not
+// copied). nil values set the target to its zero value.
+//
+// If a value cannot be decoded, the returned error is a *DecodeError
+// holding the concrete path of the value (including path).
+//
+// opts may be nil in which case the default options are used.
+//
+// If path is empty or nil, v itself is decoded.
+func DecodeWith(v interface{}, out interface{}, opts *DecodeOpts, path ...interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("expected non-nil pointer, got: %T", out)
+	}
+
+	v, err := Get(v, path...)
+	if err != nil {
+		return err
+	}
+
+	if opts == nil {
+		opts = &DecodeOpts{}
+	}
+	d := &decoder{opts: opts, tags: defaultTagKeys}
+	if opts.TagName != "" {
+		d.tags = []string{opts.TagName}
+	}
+	return d.decode(v, rv.Elem(), copyPath(path))
+}
+
+// decoder holds the state of a DecodeWith call.
+type decoder struct {
+	opts *DecodeOpts
+	tags []string // Struct tag keys naming fields
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// decode decodes value into rv, path is the concrete path of value.
+func (d *decoder) decode(value interface{}, rv reflect.Value, path []interface{}) error {
+	t := rv.Type()
+	if value == nil {
+		rv.Set(reflect.Zero(t))
+		return nil
+	}
+	if reflect.TypeOf(value).AssignableTo(t) {
+		rv.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	var err error
+	switch t {
+	case durationType:
+		unit := d.opts.DurationUnit
+		if unit == 0 {
+			unit = time.Nanosecond
+		}
+		var dur time.Duration
+		if dur, err = GetDurationUnit(value, unit); err == nil {
+			rv.SetInt(int64(dur))
+		}
+	case timeType:
+		var tm time.Time
+		if tm, err = GetTimeOpts(value, d.opts.TimeOpts); err == nil {
+			rv.Set(reflect.ValueOf(tm))
+		}
+	default:
+		var handled bool
+		if handled, err = unmarshal(value, rv); handled {
+			break
+		}
+
+		switch t.Kind() {
+		case reflect.Ptr:
+			if rv.IsNil() {
+				rv.Set(reflect.New(t.Elem()))
+			}
+			return d.decode(value, rv.Elem(), path)
+		case reflect.Struct:
+			return d.decodeStruct(value, rv, path)
+		case reflect.Map:
+			return d.decodeMap(value, rv, path)
+		case reflect.Slice, reflect.Array:
+			return d.decodeSlice(value, rv, path)
+		case reflect.Bool:
+			var b bool
+			if b, err = GetBoolean(value); err == nil {
+				rv.SetBool(b)
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			var n int64
+			if n, err = GetInteger(value); err == nil {
+				if rv.OverflowInt(n) {
+					err = fmt.Errorf("value out of range for %v: %d", t, n)
+				} else {
+					rv.SetInt(n)
+				}
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			var n uint64
+			if n, err = decodeUint(value); err == nil {
+				if rv.OverflowUint(n) {
+					err = fmt.Errorf("value out of range for %v: %d", t, n)
+				} else {
+					rv.SetUint(n)
+				}
+			}
+		case reflect.Float32, reflect.Float64:
+			var f float64
+			if f, err = GetFloating(value); err == nil {
+				if rv.OverflowFloat(f) {
+					err = fmt.Errorf("value out of range for %v: %v", t, f)
+				} else {
+					rv.SetFloat(f)
+				}
+			}
+		case reflect.String:
+			var s string
+			if s, err = GetText(value); err == nil {
+				rv.SetString(s)
+			}
+		default:
+			err = fmt.Errorf("cannot decode %T into %v", value, t)
+		}
+	}
+
+	if err != nil {
+		return &DecodeError{Path: path, Err: err}
+	}
+	return nil
+}
+
+// unmarshal decodes value into rv using the json.Unmarshaler or (if value
+// is a string) the encoding.TextUnmarshaler implementation of rv, if it has
+// one. handled tells if it did.
+func unmarshal(value interface{}, rv reflect.Value) (handled bool, err error) {
+	if !rv.CanAddr() {
+		return false, nil
+	}
+
+	switch u := rv.Addr().Interface().(type) {
+	case json.Unmarshaler:
+		data, err := json.Marshal(jsonCompatible(value))
+		if err != nil {
+			return true, err
+		}
+		return true, u.UnmarshalJSON(data)
+	case encoding.TextUnmarshaler:
+		if s, ok := value.(string); ok {
+			return true, u.UnmarshalText([]byte(s))
+		}
+	}
+	return false, nil
+}
+
+// decodeUint returns value as an uint64 using the rules of GetInteger.
+// Unsigned integers are returned as-is, so they may exceed math.MaxInt64.
+func decodeUint(value interface{}) (uint64, error) {
+	switch n := value.(type) {
+	case uint64:
+		return n, nil
+	case uint:
+		return uint64(n), nil
+	}
+
+	n, err := GetInteger(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("expected non-negative number, got: %d", n)
+	}
+	return uint64(n), nil
+}
+
+// decodeStruct decodes the map value into the struct rv.
+func (d *decoder) decodeStruct(value interface{}, rv reflect.Value, path []interface{}) error {
+	keys, elems, mapLike, _ := decodeEntries(value)
+	if !mapLike {
+		return &DecodeError{Path: path, Err: fmt.Errorf("expected map, got: %T", value)}
+	}
+
+	fields := fieldsOfTags(rv.Type(), d.tags)
+	for i, key := range keys {
+		p := appendPath(path, key)
+
+		var index []int
+		if name, ok := key.(string); ok {
+			index = lookupField(fields, name)
+		}
+		if index == nil {
+			if err := d.unknown(p); err != nil {
+				return err
+			}
+			continue
+		}
+
+		field, err := fieldAlloc(rv, index)
+		if err != nil {
+			return &DecodeError{Path: p, Err: err}
+		}
+		if err := d.decode(elems[i], field, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unknown handles the map key denoted by path having no struct field.
+func (d *decoder) unknown(path []interface{}) error {
+	if d.opts.Unknown != nil {
+		d.opts.Unknown(path)
+	}
+	if d.opts.ErrorUnknown {
+		return &DecodeError{Path: path, Err: fmt.Errorf("unknown field: %v", path[len(path)-1])}
+	}
+	return nil
+}
+
+// decodeMap decodes the map value into the map rv.
+// Entries of a non-nil rv are kept (unless overwritten).
+func (d *decoder) decodeMap(value interface{}, rv reflect.Value, path []interface{}) error {
+	keys, elems, mapLike, _ := decodeEntries(value)
+	if !mapLike {
+		return &DecodeError{Path: path, Err: fmt.Errorf("expected map, got: %T", value)}
+	}
+
+	t := rv.Type()
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(t, len(keys)))
+	}
+	for i, key := range keys {
+		p := appendPath(path, key)
+		k := reflect.New(t.Key()).Elem()
+		if err := d.decode(key, k, p); err != nil {
+			return err
+		}
+		e := reflect.New(t.Elem()).Elem()
+		if err := d.decode(elems[i], e, p); err != nil {
+			return err
+		}
+		rv.SetMapIndex(k, e)
+	}
+	return nil
+}
+
+// decodeSlice decodes the slice value into the slice or array rv.
+// Array elements not present in value are zeroed.
+func (d *decoder) decodeSlice(value interface{}, rv reflect.Value, path []interface{}) error {
+	t := rv.Type()
+	if s, ok := value.(string); ok && t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		rv.SetBytes([]byte(s))
+		return nil
+	}
+
+	keys, elems, _, listLike := decodeEntries(value)
+	if !listLike {
+		return &DecodeError{Path: path, Err: fmt.Errorf("expected slice, got: %T", value)}
+	}
+
+	if t.Kind() == reflect.Array {
+		if len(elems) > t.Len() {
+			return &DecodeError{Path: path, Err: fmt.Errorf("too many elements for %v: %d", t, len(elems))}
+		}
+		for i := len(elems); i < t.Len(); i++ {
+			rv.Index(i).Set(reflect.Zero(t.Elem()))
+		}
+	} else {
+		rv.Set(reflect.MakeSlice(t, len(elems), len(elems)))
+	}
+
+	for i, el := range elems {
+		if err := d.decode(el, rv.Index(i), appendPath(path, keys[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeEntries returns the keys and elements of the source node value
+// (map keys in sorted order), and whether it can be decoded as a map and as
+// a slice. Containers can be decoded as both.
+func decodeEntries(value interface{}) (keys, elems []interface{}, mapLike, listLike bool) {
+	collect := func(key, child interface{}) error {
+		keys, elems = append(keys, key), append(elems, child)
+		return nil
+	}
+
+	switch n := value.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeysS(n) {
+			collect(k, n[k])
+		}
+		return keys, elems, true, false
+	case map[interface{}]interface{}:
+		for _, k := range sortedKeysI(n) {
+			collect(k, n[k])
+		}
+		return keys, elems, true, false
+	case []interface{}:
+		for i, el := range n {
+			collect(i, el)
+		}
+		return keys, elems, false, true
+	case Container:
+		containerChildren(n, collect)
+		return keys, elems, true, true
+	}
+
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Map:
+		reflectChildren(rv, collect)
+		return keys, elems, true, false
+	case reflect.Slice, reflect.Array:
+		reflectChildren(rv, collect)
+		return keys, elems, false, true
+	}
+	return nil, nil, false, false
+}
+
+// lookupField returns the index sequence of the field named name, matched
+// exactly or else case-insensitively. Returns nil if there is no such field.
+func lookupField(fields *structFields, name string) []int {
+	if index, ok := fields.byName[name]; ok {
+		return index
+	}
+	for _, n := range fields.names {
+		if strings.EqualFold(n, name) {
+			return fields.byName[n]
+		}
+	}
+	return nil
+}
+
+// fieldAlloc returns the field of the struct rv denoted by the index
+// sequence, allocating nil embedded struct pointers on the way.
+func fieldAlloc(rv reflect.Value, index []int) (reflect.Value, error) {
+	for j, x := range index {
+		if j > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot allocate unexported embedded %v", rv.Type())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, nil
+}
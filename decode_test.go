@@ -0,0 +1,145 @@
+package dyno
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testServer struct {
+	Host    string            `json:"host"`
+	Port    uint16            `json:"port"`
+	TLS     bool              `json:"tls"`
+	Timeout time.Duration     `json:"timeout"`
+	Started time.Time         `json:"started"`
+	Ratio   float32           `json:"ratio"`
+	Tags    []string          `json:"tags"`
+	Limits  map[string]int    `json:"limits"`
+	Backup  *testServer       `json:"backup"`
+	Extra   interface{}       `json:"extra"`
+	Codes   map[int]string    `json:"codes"`
+	Pair    [2]int            `json:"pair"`
+	Raw     []byte            `json:"raw"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+func TestDecode(t *testing.T) {
+	started := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		title string      // Title of the test case
+		v     interface{} // Input dynamic object
+		out   interface{} // Pointer to decode into
+		exp   interface{} // Expected decoded value (pointed by out)
+		isErr bool        // Tells if error is expected
+	}{
+		// Test success:
+		{
+			"struct",
+			map[string]interface{}{
+				"host": "h", "port": 80.0, "tls": "true", "timeout": "1m", "started": "2020-01-02T03:04:05Z",
+				"ratio": 0.5, "tags": []interface{}{"a", 1}, "limits": map[interface{}]interface{}{"x": "2"},
+				"backup": map[string]interface{}{"host": "b"}, "extra": []interface{}{1},
+				"codes": map[string]interface{}{"404": "nf"}, "pair": []interface{}{1},
+				"raw": "r", "labels": map[string]interface{}{"l": "v"},
+			},
+			&testServer{},
+			testServer{
+				Host: "h", Port: 80, TLS: true, Timeout: time.Minute, Started: started,
+				Ratio: 0.5, Tags: []string{"a", "1"}, Limits: map[string]int{"x": 2},
+				Backup: &testServer{Host: "b"}, Extra: []interface{}{1},
+				Codes: map[int]string{404: "nf"}, Pair: [2]int{1, 0},
+				Raw: []byte("r"), Labels: map[string]string{"l": "v"},
+			},
+			false,
+		},
+		{"case-insensitive", map[string]interface{}{"HOST": "h", "Port": 1}, &testServer{}, testServer{Host: "h", Port: 1}, false},
+		{"unknown ignored", map[string]interface{}{"x": 1}, &testServer{}, testServer{}, false},
+		{"nil", nil, &testServer{Host: "h"}, testServer{}, false},
+		{"slice", []interface{}{1, "2", 3.0}, &[]int{}, []int{1, 2, 3}, false},
+		{"map", map[string]interface{}{"a": 1}, &map[string]float64{}, map[string]float64{"a": 1}, false},
+		{"assignable", []interface{}{1}, new(interface{}), []interface{}{1}, false},
+		{"typed slice", []string{"1"}, &[]int{}, []int{1}, false},
+		{"list container", newTestList(1, 2), &[]int{}, []int{1, 2}, false},
+
+		// Test errors:
+		{"type error", map[string]interface{}{"port": "x"}, &testServer{}, testServer{}, true},
+		{"overflow error", map[string]interface{}{"port": 70000}, &testServer{}, testServer{}, true},
+		{"negative error", map[string]interface{}{"port": -1}, &testServer{}, testServer{}, true},
+		{"expected map error", []interface{}{}, &testServer{}, testServer{}, true},
+		{"expected slice error", map[string]interface{}{}, &[]int{}, []int{}, true},
+		{"array length error", map[string]interface{}{"pair": []interface{}{1, 2, 3}}, &testServer{}, testServer{}, true},
+	}
+
+	for _, c := range cases {
+		err := Decode(c.v, c.out)
+		if got := reflect.ValueOf(c.out).Elem().Interface(); !c.isErr && !reflect.DeepEqual(got, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, got)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	// Non-pointer out:
+	if err := Decode(map[string]interface{}{}, testServer{}); err == nil {
+		t.Errorf("Expected error for non-pointer out")
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	v := map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"host": "a"},
+			map[string]interface{}{"host": "b", "backup": map[string]interface{}{"port": "x"}},
+		},
+	}
+
+	var servers []testServer
+	err := Decode(v, &servers, "servers")
+	var derr *DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("Expected *DecodeError, got: %v", err)
+	}
+	if exp := []interface{}{"servers", 1, "backup", "port"}; !reflect.DeepEqual(derr.Path, exp) {
+		t.Errorf("Expected path: %v, got: %v", exp, derr.Path)
+	}
+
+	if err := Decode(v, &servers, "x"); err == nil {
+		t.Errorf("Expected error for missing path")
+	}
+}
+
+func TestDecodeWith(t *testing.T) {
+	type config struct {
+		Name    string        `yaml:"name" json:"title"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+	v := map[interface{}]interface{}{"name": "n", "timeout": 3, "x": 1, "y": 2}
+
+	var unknown [][]interface{}
+	opts := &DecodeOpts{
+		TagName:      "yaml",
+		DurationUnit: time.Second,
+		Unknown:      func(path []interface{}) { unknown = append(unknown, path) },
+	}
+	var c config
+	if err := DecodeWith(v, &c, opts); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if exp := (config{Name: "n", Timeout: 3 * time.Second}); c != exp {
+		t.Errorf("Expected value: %v, got: %v", exp, c)
+	}
+	if exp := [][]interface{}{{"x"}, {"y"}}; !reflect.DeepEqual(unknown, exp) {
+		t.Errorf("Expected unknown: %v, got: %v", exp, unknown)
+	}
+
+	// By default json tags take precedence, so "name" is unknown:
+	opts = &DecodeOpts{ErrorUnknown: true}
+	err := DecodeWith(v, &c, opts)
+	var derr *DecodeError
+	if !errors.As(err, &derr) || !reflect.DeepEqual(derr.Path, []interface{}{"name"}) {
+		t.Errorf("Expected unknown field error at path [name], got: %v", err)
+	}
+}
@@ -16,7 +16,11 @@ Should you need to marshal a dynamic object to JSON which contains maps with
 interface{} key type (which is not supported by encoding/json), you may use
 the ConvertMapI2MapS converter function.
 
-The implementation does not use reflection at all, so performance is rather good.
+The implementation does not use reflection for the types listed above, so
+performance is rather good. Go structs are navigated by field name or json / yaml
+tag name. Other maps, slices and arrays (e.g. map[string]string or
+[]map[string]interface{}) can be handled by enabling the opt-in reflection
+fallback, see ReflectFallback.
 
 Let's see a simple example editing a JSON text to mask out a password. This is
 a simplified version of the Example_jsonEdit example function:
@@ -78,7 +82,21 @@ func Get(v interface{}, path ...interface{}) (interface{}, error) {
 			v = node[idx]
 
 		default:
-			return nil, fmt.Errorf("expected map or slice node, got: %T (path element idx: %d)", node, i)
+			var exists bool
+			var err error
+			if c, ok := node.(Container); ok {
+				v, exists, err = containerGetElem(c, el, i)
+			} else if rv, ok := reflectNode(node); ok {
+				v, exists, err = reflectGetElem(rv, el, i)
+			} else {
+				return nil, fmt.Errorf("expected map or slice node, got: %T (path element idx: %d)", node, i)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				return nil, fmt.Errorf("missing key: %v (path element idx: %d)", el, i)
+			}
 		}
 	}
 
@@ -383,22 +401,71 @@ func SGet(m map[string]interface{}, path ...string) (interface{}, error) {
 //
 // Path cannot be empty or nil, else an error is returned.
 func Set(v interface{}, value interface{}, path ...interface{}) error {
-	if len(path) == 0 {
-		return fmt.Errorf("path cannot be empty")
+	node, err := getParent(v, path)
+	if err != nil {
+		return err
 	}
 
 	i := len(path) - 1 // The last index
-	if len(path) > 1 {
-		var err error
-		v, err = Get(v, path[:i]...)
-		if err != nil {
-			return err
+	return setElem(node, path[i], i, value)
+}
+
+// getParent returns the node denoted by the path without its last element,
+// that is the map or slice that holds the value denoted by the path.
+//
+// Path cannot be empty or nil, else an error is returned.
+func getParent(v interface{}, path []interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+
+	return Get(v, path[:len(path)-1]...)
+}
+
+// getElem returns the element of the map or slice node denoted by the
+// path element el whose index in the path is i.
+//
+// exists tells if the element exists. A missing map key is not an error,
+// but an invalid slice index is.
+func getElem(node interface{}, el interface{}, i int) (value interface{}, exists bool, err error) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		key, ok := el.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("expected string path element, got: %T (path element idx: %d)", el, i)
 		}
+		value, exists = node[key]
+
+	case map[interface{}]interface{}:
+		value, exists = node[el]
+
+	case []interface{}:
+		idx, ok := el.(int)
+		if !ok {
+			return nil, false, fmt.Errorf("expected int path element, got: %T (path element idx: %d)", el, i)
+		}
+		if idx < 0 || idx >= len(node) {
+			return nil, false, fmt.Errorf("index out of range: %d (path element idx: %d)", idx, i)
+		}
+		value, exists = node[idx], true
+
+	default:
+		if c, ok := node.(Container); ok {
+			return containerGetElem(c, el, i)
+		}
+		if rv, ok := reflectNode(node); ok {
+			return reflectGetElem(rv, el, i)
+		}
+		return nil, false, fmt.Errorf("expected map or slice node, got: %T (path element idx: %d)", node, i)
 	}
 
-	el := path[i]
+	return
+}
 
-	switch node := v.(type) {
+// setElem sets the element of the map or slice node denoted by the
+// path element el whose index in the path is i.
+func setElem(node interface{}, el interface{}, i int, value interface{}) error {
+	switch node := node.(type) {
 	case map[string]interface{}:
 		key, ok := el.(string)
 		if !ok {
@@ -420,6 +487,12 @@ func Set(v interface{}, value interface{}, path ...interface{}) error {
 		node[idx] = value
 
 	default:
+		if c, ok := node.(Container); ok {
+			return containerSetElem(c, el, i, value)
+		}
+		if rv, ok := reflectNode(node); ok {
+			return reflectSetElem(rv, el, i, value)
+		}
 		return fmt.Errorf("expected map or slice node, got: %T (path element idx: %d)", node, i)
 	}
 
@@ -479,6 +552,12 @@ func Append(v interface{}, value interface{}, path ...interface{}) error {
 
 	s, ok := node.([]interface{})
 	if !ok {
+		if c, ok := node.(Container); ok {
+			return c.SetElem(c.Len(), value)
+		}
+		if rv, ok := reflectNode(node); ok {
+			return reflectAppend(v, rv, []interface{}{value}, path)
+		}
 		return fmt.Errorf("expected slice node, got: %T (path element idx: %d)", node, len(path)-1)
 	}
 
@@ -503,6 +582,17 @@ func AppendMore(v interface{}, values []interface{}, path ...interface{}) error
 
 	s, ok := node.([]interface{})
 	if !ok {
+		if c, ok := node.(Container); ok {
+			for _, value := range values {
+				if err := c.SetElem(c.Len(), value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if rv, ok := reflectNode(node); ok {
+			return reflectAppend(v, rv, values, path)
+		}
 		return fmt.Errorf("expected slice node, got: %T (path element idx: %d)", node, len(path))
 	}
 
@@ -554,6 +644,12 @@ func Delete(v interface{}, key interface{}, path ...interface{}) error {
 		return Set(v, node2[:len(node2)-1], path...)
 
 	default:
+		if c, ok := node.(Container); ok {
+			return c.DeleteElem(key)
+		}
+		if rv, ok := reflectNode(node); ok {
+			return reflectDelete(v, rv, key, path)
+		}
 		return fmt.Errorf("expected map or slice node, got: %T (path element idx: %d)", node, len(path)-1)
 	}
 
@@ -569,6 +665,7 @@ func Delete(v interface{}, key interface{}, path ...interface{}) error {
 //   -map[interface{}]interface{}
 //   -map[string]interface{}
 //   -[]interface{}
+//   -Container (elements are converted in place)
 //
 // When converting map[interface{}]interface{} to map[string]interface{},
 // fmt.Sprint() with default formatting is used to convert the key to a string key.
@@ -595,6 +692,11 @@ func ConvertMapI2MapS(v interface{}) interface{} {
 		for k, v2 := range x {
 			x[k] = ConvertMapI2MapS(v2)
 		}
+
+	case Container:
+		containerChildren(x, func(key, child interface{}) error {
+			return x.SetElem(key, ConvertMapI2MapS(child))
+		})
 	}
 
 	return v
@@ -0,0 +1,307 @@
+package dyno
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FromValueOpts holds options for FromValue.
+type FromValueOpts struct {
+	// TagName is the struct tag key naming struct fields (e.g. "yaml" or
+	// "mapstructure"). If empty, json tag names are used, falling back to
+	// yaml tag names (like when navigating into structs, see
+	// ReflectFallback).
+	TagName string
+
+	// KeepTypes lists types whose values are kept as-is instead of being
+	// converted, e.g. reflect.TypeOf(time.Time{}) to keep time.Time values
+	// (which would be rendered as text otherwise).
+	KeepTypes []reflect.Type
+}
+
+// FromValue converts the Go value x into a dynamic object without
+// serialization (unlike a JSON marshal-unmarshal round trip).
+//
+// Values are converted according to their types, namely:
+//   -nil pointers, interfaces, maps and slices to nil
+//   -types implementing json.Marshaler: their JSON output unmarshaled
+//   -types implementing encoding.TextMarshaler: their text as a string
+//   -struct to map[string]interface{}: fields are named like in
+//    DecodeWith (see FromValueOpts.TagName), fields tagged with "-" and
+//    unexported fields are excluded, fields of embedded structs are
+//    promoted; the tag options "omitempty" (omit zero values) and "string"
+//    (render bool and number values as strings) are honored
+//   -map to map[string]interface{} if its key type is of string kind, else
+//    to map[interface{}]interface{}, keys being converted like values
+//   -[]byte is copied, other slices and arrays to []interface{}
+//   -pointer and interface: the pointed / wrapped value is converted
+//   -bool, integer, floating point and string types to the predeclared
+//    type of the same kind (e.g. a value of type Color with underlying
+//    type string becomes a string)
+// Other types (e.g. channels, functions and complex numbers) and cyclic
+// values (e.g. a struct pointing to itself) result in an error.
+//
+// The result can be decoded back using Decode.
+//
+// opts may be nil in which case the default options are used.
+func FromValue(x interface{}, opts *FromValueOpts) (interface{}, error) {
+	if opts == nil {
+		opts = &FromValueOpts{}
+	}
+	e := &encoder{opts: opts, tags: defaultTagKeys, active: map[encodeKey]bool{}}
+	if opts.TagName != "" {
+		e.tags = []string{opts.TagName}
+	}
+	return e.encode(reflect.ValueOf(x), []interface{}{})
+}
+
+// encoder holds the state of a FromValue call.
+type encoder struct {
+	opts   *FromValueOpts
+	tags   []string           // Struct tag keys naming fields
+	active map[encodeKey]bool // Pointers, maps and slices being encoded (to detect cycles)
+}
+
+// encodeKey identifies a pointer, map or slice value.
+type encodeKey struct {
+	ptr uintptr
+	t   reflect.Type
+	len int // Length of slices (a subslice sharing its first element is a different value)
+}
+
+// encode converts rv, path is the concrete path of rv in the result.
+func (e *encoder) encode(rv reflect.Value, path []interface{}) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+	if rv.Kind() == reflect.Interface {
+		return e.encode(rv.Elem(), path)
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		key := encodeKey{ptr: rv.Pointer(), t: rv.Type()}
+		if rv.Kind() == reflect.Slice {
+			key.len = rv.Len()
+		}
+		if e.active[key] {
+			return nil, fmt.Errorf("encountered a cycle via %v (path: %v)", rv.Type(), path)
+		}
+		e.active[key] = true
+		defer delete(e.active, key)
+	}
+
+	t := rv.Type()
+	for _, kt := range e.opts.KeepTypes {
+		if t == kt {
+			return rv.Interface(), nil
+		}
+	}
+
+	if m, ok := implementer(rv, jsonMarshalerType); ok {
+		data, err := m.(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("%v (path: %v)", err, path)
+		}
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("%v (path: %v)", err, path)
+		}
+		return v, nil
+	}
+	if m, ok := implementer(rv, textMarshalerType); ok {
+		data, err := m.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("%v (path: %v)", err, path)
+		}
+		return string(data), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		return e.encode(rv.Elem(), path)
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int:
+		return int(rv.Int()), nil
+	case reflect.Int8:
+		return int8(rv.Int()), nil
+	case reflect.Int16:
+		return int16(rv.Int()), nil
+	case reflect.Int32:
+		return int32(rv.Int()), nil
+	case reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint:
+		return uint(rv.Uint()), nil
+	case reflect.Uint8:
+		return uint8(rv.Uint()), nil
+	case reflect.Uint16:
+		return uint16(rv.Uint()), nil
+	case reflect.Uint32:
+		return uint32(rv.Uint()), nil
+	case reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32:
+		return float32(rv.Float()), nil
+	case reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Struct:
+		return e.encodeStruct(rv, path)
+	case reflect.Map:
+		return e.encodeMap(rv, path)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), rv.Bytes()...), nil
+		}
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			v, err := e.encode(rv.Index(i), appendPath(path, i))
+			if err != nil {
+				return nil, err
+			}
+			s[i] = v
+		}
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type: %v (path: %v)", t, path)
+}
+
+// encodeStruct converts the struct rv to a map[string]interface{}.
+func (e *encoder) encodeStruct(rv reflect.Value, path []interface{}) (interface{}, error) {
+	t := rv.Type()
+	fields := fieldsOfTags(t, e.tags)
+	m := make(map[string]interface{}, len(fields.names))
+	for _, name := range fields.names {
+		index := fields.byName[name]
+		field, exists := fieldByIndex(rv, index)
+		if !exists {
+			continue
+		}
+
+		omitEmpty, asString := fieldTagOpts(t.FieldByIndex(index), e.tags)
+		if omitEmpty && isEmptyValue(field) {
+			continue
+		}
+
+		v, err := e.encode(field, appendPath(path, name))
+		if err != nil {
+			return nil, err
+		}
+		if asString && (isNumber(v) || field.Kind() == reflect.Bool) {
+			if v, err = GetText(v); err != nil {
+				return nil, err
+			}
+		}
+		m[name] = v
+	}
+	return m, nil
+}
+
+// encodeMap converts the map rv to a map[string]interface{} if its key type
+// is of string kind, else to a map[interface{}]interface{}.
+func (e *encoder) encodeMap(rv reflect.Value, path []interface{}) (interface{}, error) {
+	t := rv.Type()
+	if t.Key().Kind() == reflect.String {
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			v, err := e.encode(iter.Value(), appendPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	}
+
+	m := make(map[interface{}]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		k, err := e.encode(iter.Key(), path)
+		if err != nil {
+			return nil, err
+		}
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			return nil, fmt.Errorf("unsupported map key type: %v (path: %v)", t.Key(), path)
+		}
+		v, err := e.encode(iter.Value(), appendPath(path, k))
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// implementer returns rv (or its address if rv is addressable and only its
+// pointer type does) as an interface{} if it implements the interface type
+// it.
+func implementer(rv reflect.Value, it reflect.Type) (interface{}, bool) {
+	if rv.Type().Implements(it) {
+		return rv.Interface(), true
+	}
+	if rv.CanAddr() && reflect.PtrTo(rv.Type()).Implements(it) {
+		return rv.Addr().Interface(), true
+	}
+	return nil, false
+}
+
+// fieldTagOpts returns the omitempty and string options of the struct
+// field f, taken from the first of the given tag keys present.
+func fieldTagOpts(f reflect.StructField, tags []string) (omitEmpty, asString bool) {
+	for _, key := range tags {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		for _, opt := range strings.Split(tag, ",")[1:] {
+			switch opt {
+			case "omitempty":
+				omitEmpty = true
+			case "string":
+				asString = true
+			}
+		}
+		return
+	}
+	return
+}
+
+// isEmptyValue tells if rv is the zero value of its type as defined by the
+// omitempty option of encoding/json.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+	return false
+}
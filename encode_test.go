@@ -0,0 +1,166 @@
+package dyno
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testColor string
+
+// testLevel implements encoding.TextMarshaler and encoding.TextUnmarshaler.
+type testLevel int
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	*l = testLevel(len(text))
+	return nil
+}
+
+// testPoint implements json.Marshaler and json.Unmarshaler.
+type testPoint struct {
+	X, Y int
+}
+
+func (p testPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.X, p.Y})
+}
+
+func (p *testPoint) UnmarshalJSON(data []byte) error {
+	var xy []int
+	if err := json.Unmarshal(data, &xy); err != nil {
+		return err
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
+
+type testShape struct {
+	testBase
+	Color   testColor         `json:"color"`
+	Level   testLevel         `json:"level"`
+	Center  testPoint         `json:"center"`
+	Points  []*testPoint      `json:"points,omitempty"`
+	Size    int               `json:"size,string"`
+	Visible bool              `json:"visible,omitempty"`
+	Names   map[int]string    `json:"names,omitempty"`
+	Attrs   map[string]uint8  `json:"attrs"`
+	Created time.Time         `json:"created"`
+	Hidden  string            `json:"-"`
+	Parent  *testShape        `json:"parent"`
+	Data    []byte            `json:"data"`
+	Extra   map[string]string `json:"extra,omitempty"`
+}
+
+func TestFromValue(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	shape := &testShape{
+		testBase: testBase{ID: 1, Kind: "k"},
+		Color:    "red",
+		Level:    3,
+		Center:   testPoint{1, 2},
+		Size:     10,
+		Names:    map[int]string{1: "one"},
+		Attrs:    map[string]uint8{"a": 1},
+		Created:  created,
+		Hidden:   "h",
+		Data:     []byte("d"),
+	}
+	cyclic := &testShape{}
+	cyclic.Parent = cyclic
+	shared := &testPoint{1, 2}
+
+	cases := []struct {
+		title string         // Title of the test case
+		x     interface{}    // Input value
+		opts  *FromValueOpts // Options
+		exp   interface{}    // Expected result
+		isErr bool           // Tells if error is expected
+	}{
+		// Test success:
+		{
+			"struct", shape, nil,
+			map[string]interface{}{
+				"id": 1, "kind": "k", "color": "red", "level": "***", "center": []interface{}{1.0, 2.0},
+				"size": "10", "names": map[interface{}]interface{}{1: "one"}, "attrs": map[string]interface{}{"a": uint8(1)},
+				"created": "2020-01-02T03:04:05Z", "parent": nil, "data": []byte("d"),
+			},
+			false,
+		},
+		{"nil", nil, nil, nil, false},
+		{"nil pointer", (*testShape)(nil), nil, nil, false},
+		{"slice", []testColor{"a"}, nil, []interface{}{"a"}, false},
+		{"array", [2]int8{1, 2}, nil, []interface{}{int8(1), int8(2)}, false},
+		{"dynamic object", map[string]interface{}{"a": []interface{}{1}}, nil, map[string]interface{}{"a": []interface{}{1}}, false},
+		{"shared pointer", []*testPoint{shared, shared}, nil, []interface{}{[]interface{}{1.0, 2.0}, []interface{}{1.0, 2.0}}, false},
+		{
+			"keep types", map[string]time.Time{"t": created},
+			&FromValueOpts{KeepTypes: []reflect.Type{reflect.TypeOf(time.Time{})}},
+			map[string]interface{}{"t": created},
+			false,
+		},
+		{
+			"tag name", struct {
+				A int `yaml:"a" json:"x"`
+				B int `yaml:"-"`
+			}{1, 2},
+			&FromValueOpts{TagName: "yaml"},
+			map[string]interface{}{"a": 1},
+			false,
+		},
+
+		// Test errors:
+		{"chan error", map[string]interface{}{"c": make(chan int)}, nil, nil, true},
+		{"complex error", []complex64{1}, nil, nil, true},
+		{"cycle error", cyclic, nil, nil, true},
+	}
+
+	for _, c := range cases {
+		v, err := FromValue(c.x, c.opts)
+		if !reflect.DeepEqual(v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, v)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestFromValueRoundTrip(t *testing.T) {
+	shape := testShape{
+		testBase: testBase{ID: 1, Kind: "k"},
+		Color:    "red",
+		Level:    3,
+		Center:   testPoint{1, 2},
+		Points:   []*testPoint{{3, 4}},
+		Size:     10,
+		Visible:  true,
+		Names:    map[int]string{1: "one"},
+		Attrs:    map[string]uint8{"a": 1},
+		Created:  time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Parent:   &testShape{Color: "blue"},
+		Data:     []byte("d"),
+	}
+
+	v, err := FromValue(shape, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := Set(v, "green", "parent", "color"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	var got testShape
+	if err := Decode(v, &got); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	shape.Parent.Color = "green"
+	if !reflect.DeepEqual(got, shape) {
+		t.Errorf("Expected value: %+v, got: %+v", shape, got)
+	}
+}
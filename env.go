@@ -0,0 +1,243 @@
+package dyno
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvOpts holds options for ApplyEnv.
+type EnvOpts struct {
+	// Environ lists the environment variables in "key=value" form.
+	// If nil, os.Environ() is used.
+	Environ []string
+
+	// Separator of the path elements in variable names (after the prefix).
+	// Defaults to "__", so APP_DB__HOST denotes the path db, host.
+	Separator string
+
+	// CaseSensitive tells to match the prefix and existing map keys
+	// case-sensitively, else they are matched case-insensitively.
+	CaseSensitive bool
+
+	// KeepCase tells to keep the case of path elements when creating new
+	// map keys (see Create), else they are lower-cased.
+	KeepCase bool
+
+	// Create tells to create map keys (and intermediate maps) not present
+	// in v, else variables denoting missing keys are ignored.
+	Create bool
+}
+
+// ApplyEnv overlays environment variables having the given prefix onto the
+// dynamic object v, e.g. with prefix "APP_" the variable APP_DB__HOST=x sets
+// the value denoted by the path db, host to "x". opts may be nil, in which
+// case the zero value of EnvOpts is used.
+//
+// The rest of the variable name (after the prefix) is split by the
+// separator into path elements. Path elements are matched to existing map
+// keys (see EnvOpts.CaseSensitive) and are parsed as indices for slices.
+//
+// Values are coerced to the type of the existing value:
+//   -bool: parsed using the rules of GetBoolean
+//   -integer types, *big.Int: parsed using the rules of GetInteger
+//   -floating point types, json.Number: parsed using the rules of GetFloating
+//   -time.Duration: parsed using the rules of GetDuration
+//   -maps and slices: the value is decoded as JSON (into an *OrderedMap
+//    if the existing value is one)
+//   -string and nil: the value is used as-is
+// New values (see EnvOpts.Create) are strings.
+//
+// Variables are applied in sorted order. An error is returned for the first
+// variable whose path is invalid (e.g. an invalid slice index) or whose
+// value cannot be coerced; variables applied before that remain applied.
+func ApplyEnv(v interface{}, prefix string, opts *EnvOpts) error {
+	if opts == nil {
+		opts = &EnvOpts{}
+	}
+	environ := os.Environ()
+	if opts.Environ != nil {
+		environ = append([]string(nil), opts.Environ...)
+	}
+	sep := opts.Separator
+	if sep == "" {
+		sep = "__"
+	}
+
+	sort.Strings(environ)
+	for _, kv := range environ {
+		eq := strings.IndexByte(kv, '=')
+		if eq < 0 {
+			continue
+		}
+		name, value := kv[:eq], kv[eq+1:]
+		if len(name) <= len(prefix) || !matchKey(name[:len(prefix)], prefix, opts.CaseSensitive) {
+			continue
+		}
+
+		if err := applyEnvVar(v, strings.Split(name[len(prefix):], sep), value, opts); err != nil {
+			return fmt.Errorf("invalid environment variable %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyEnvVar sets the value denoted by the path elements segs.
+func applyEnvVar(v interface{}, segs []string, value string, opts *EnvOpts) error {
+	node := v
+	for i, seg := range segs {
+		el, exists, err := envPathElem(node, seg, i, opts)
+		if err != nil || !exists && !opts.Create {
+			return err
+		}
+		if !exists && !opts.KeepCase {
+			el = strings.ToLower(seg)
+		}
+
+		if i == len(segs)-1 {
+			if !exists {
+				return setElem(node, el, i, value)
+			}
+			cur, _, _ := getElem(node, el, i)
+			newValue, err := coerceString(cur, value)
+			if err != nil {
+				return err
+			}
+			return setElem(node, el, i, newValue)
+		}
+
+		child, _, _ := getElem(node, el, i)
+		if !exists {
+			if _, ok := node.(map[interface{}]interface{}); ok {
+				child = map[interface{}]interface{}{}
+			} else {
+				child = map[string]interface{}{}
+			}
+			if err := setElem(node, el, i, child); err != nil {
+				return err
+			}
+		}
+		node = child
+	}
+	return nil
+}
+
+// envPathElem returns the path element of node matching seg, and whether
+// it exists. i is the index of the path element.
+func envPathElem(node interface{}, seg string, i int, opts *EnvOpts) (el interface{}, exists bool, err error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if _, ok := n[seg]; ok {
+			return seg, true, nil
+		}
+		if !opts.CaseSensitive {
+			for _, k := range sortedKeysS(n) {
+				if strings.EqualFold(k, seg) {
+					return k, true, nil
+				}
+			}
+		}
+		return seg, false, nil
+
+	case map[interface{}]interface{}:
+		if _, ok := n[seg]; ok {
+			return seg, true, nil
+		}
+		for _, k := range sortedKeysI(n) {
+			if ks, ok := k.(string); ok && matchKey(ks, seg, opts.CaseSensitive) {
+				return k, true, nil
+			}
+		}
+		return seg, false, nil
+
+	case []interface{}:
+		idx, err := strconv.Atoi(seg)
+		if err != nil || idx < 0 || idx >= len(n) {
+			return nil, false, fmt.Errorf("invalid index: %q (path element idx: %d)", seg, i)
+		}
+		return idx, true, nil
+
+	case Container:
+		if _, exists, err := n.GetElem(seg); err == nil && exists {
+			return seg, true, nil
+		}
+		var found interface{}
+		n.Range(func(k, v interface{}) bool {
+			if ks, ok := k.(string); ok && matchKey(ks, seg, opts.CaseSensitive) {
+				found = k
+				return false
+			}
+			return true
+		})
+		if found != nil {
+			return found, true, nil
+		}
+		if idx, err := strconv.Atoi(seg); err == nil {
+			if _, exists, err := n.GetElem(idx); err == nil && exists {
+				return idx, true, nil
+			}
+		}
+		return seg, false, nil
+	}
+
+	return nil, false, fmt.Errorf("expected map or slice node, got: %T (path element idx: %d)", node, i)
+}
+
+// matchKey tells if a and b match, case-insensitively unless caseSensitive
+// is true.
+func matchKey(a, b string, caseSensitive bool) bool {
+	if caseSensitive {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
+
+// coerceString converts s to a value having the same type as kind.
+func coerceString(kind interface{}, s string) (interface{}, error) {
+	switch kind.(type) {
+	case nil, string:
+		return s, nil
+	case bool:
+		return GetBoolean(s)
+	case time.Duration:
+		return GetDuration(s)
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("invalid JSON value: %v", err)
+		}
+		return v, nil
+	case *OrderedMap:
+		v, err := UnmarshalOrdered([]byte(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON value: %v", err)
+		}
+		return v, nil
+	case float64, float32:
+		f, err := GetFloating(s)
+		if err != nil {
+			return nil, err
+		}
+		return floatToKind(kind, f)
+	case json.Number:
+		s = strings.TrimSpace(s)
+		if !isJSONNumber(s) {
+			return nil, fmt.Errorf("invalid JSON number: %q", s)
+		}
+		return json.Number(s), nil
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8, *big.Int:
+		n, err := GetInteger(s)
+		if err != nil {
+			return nil, err
+		}
+		return ratToKind(kind, new(big.Rat).SetInt64(n))
+	}
+
+	return nil, fmt.Errorf("cannot coerce string to %T", kind)
+}
@@ -0,0 +1,164 @@
+package dyno
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyEnv(t *testing.T) {
+	// newConfig returns a fresh config, the input of each test case.
+	newConfig := func() interface{} {
+		return map[string]interface{}{
+			"db": map[interface{}]interface{}{
+				"Host":    "localhost",
+				"port":    5432,
+				"ratio":   float32(0.5),
+				"debug":   false,
+				"timeout": 3 * time.Second,
+				"tags":    []interface{}{"a"},
+			},
+			"servers": []interface{}{
+				map[string]interface{}{"name": "s1", "weight": json.Number("1")},
+			},
+		}
+	}
+
+	cases := []struct {
+		title   string        // Title of the test case
+		environ []string      // Environment variables
+		opts    *EnvOpts      // Options (Environ is set from environ)
+		path    []interface{} // Path of the value to check
+		exp     interface{}   // Expected value
+		isErr   bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title:   "string, case-insensitive key",
+			environ: []string{"APP_DB__HOST=example.com"},
+			path:    []interface{}{"db", "Host"},
+			exp:     "example.com",
+		},
+		{
+			title:   "int",
+			environ: []string{"APP_DB__PORT=6543"},
+			path:    []interface{}{"db", "port"},
+			exp:     6543,
+		},
+		{
+			title:   "float32",
+			environ: []string{"APP_DB__RATIO=0.25"},
+			path:    []interface{}{"db", "ratio"},
+			exp:     float32(0.25),
+		},
+		{
+			title:   "bool",
+			environ: []string{"APP_DB__DEBUG=true"},
+			path:    []interface{}{"db", "debug"},
+			exp:     true,
+		},
+		{
+			title:   "duration",
+			environ: []string{"APP_DB__TIMEOUT=1m"},
+			path:    []interface{}{"db", "timeout"},
+			exp:     time.Minute,
+		},
+		{
+			title:   "JSON slice",
+			environ: []string{"APP_DB__TAGS=[\"x\", 1]"},
+			path:    []interface{}{"db", "tags"},
+			exp:     []interface{}{"x", 1.0},
+		},
+		{
+			title:   "slice index and json.Number",
+			environ: []string{"APP_SERVERS__0__WEIGHT=2.5"},
+			path:    []interface{}{"servers", 0, "weight"},
+			exp:     json.Number("2.5"),
+		},
+		{
+			title:   "custom separator, lower-case prefix",
+			environ: []string{"app_db.port=1"},
+			opts:    &EnvOpts{Separator: "."},
+			path:    []interface{}{"db", "port"},
+			exp:     1,
+		},
+		{
+			title:   "missing key ignored",
+			environ: []string{"APP_DB__USER=admin", "OTHER_X=1", "APP_=1", "invalid"},
+			path:    []interface{}{"db", "user"},
+			isErr:   true,
+		},
+		{
+			title:   "missing key created",
+			environ: []string{"APP_CACHE__TTL=10"},
+			opts:    &EnvOpts{Create: true},
+			path:    []interface{}{"cache", "ttl"},
+			exp:     "10",
+		},
+		{
+			title:   "missing key created keeping case",
+			environ: []string{"APP_DB__User=admin"},
+			opts:    &EnvOpts{Create: true, KeepCase: true},
+			path:    []interface{}{"db", "User"},
+			exp:     "admin",
+		},
+		{
+			title:   "case-sensitive",
+			environ: []string{"APP_DB__HOST=example.com", "APP_db__Host=x", "app_db__Host=y"},
+			opts:    &EnvOpts{CaseSensitive: true},
+			path:    []interface{}{"db", "Host"},
+			exp:     "x",
+		},
+	}
+
+	for _, c := range cases {
+		opts := c.opts
+		if opts == nil {
+			opts = &EnvOpts{}
+		}
+		opts.Environ = c.environ
+
+		v := newConfig()
+		if err := ApplyEnv(v, "APP_", opts); err != nil {
+			t.Errorf("[title: %s] Unexpected error: %v", c.title, err)
+		}
+		value, err := Get(v, c.path...)
+		if !reflect.DeepEqual(value, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v (%T), got: %v (%T)", c.title, c.exp, c.exp, value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	errCases := []struct {
+		title   string   // Title of the test case
+		environ []string // Environment variables
+	}{
+		{"invalid int error", []string{"APP_DB__PORT=x"}},
+		{"int overflow error", []string{"APP_DB__PORT=99999999999999999999"}},
+		{"invalid JSON error", []string{"APP_DB__TAGS=[1"}},
+		{"invalid json.Number error", []string{"APP_SERVERS__0__WEIGHT=12abc"}},
+		{"invalid index error", []string{"APP_SERVERS__1__NAME=x"}},
+		{"non-container node error", []string{"APP_DB__PORT__X=1"}},
+	}
+
+	for _, c := range errCases {
+		if err := ApplyEnv(newConfig(), "APP_", &EnvOpts{Environ: c.environ}); err == nil {
+			t.Errorf("[title: %s] Expected error", c.title)
+		}
+	}
+}
+
+func TestApplyEnvOrdered(t *testing.T) {
+	v := mustOrdered(`{"DB": {"host": "h", "port": 1}, "list": [{"name": "a"}], "opts": {"x": 1}}`)
+	environ := []string{"APP_DB__HOST=x", "APP_DB__PORT=2", "APP_LIST__0__NAME=b", `APP_OPTS={"z":1,"y":2}`}
+	if err := ApplyEnv(v, "APP_", &EnvOpts{Environ: environ}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	exp := `{"DB":{"host":"x","port":2},"list":[{"name":"b"}],"opts":{"z":1,"y":2}}`
+	if data, _ := json.Marshal(v); string(data) != exp {
+		t.Errorf("Expected value: %s, got: %s", exp, data)
+	}
+}
@@ -0,0 +1,113 @@
+package dyno
+
+import (
+	"reflect"
+)
+
+// Equal tells if the dynamic objects a and b are structurally equal.
+//
+// Numbers are compared by their values regardless of their types and
+// without losing precision, so int(1), float64(1.0), json.Number("1.0")
+// and big.NewInt(1) are all equal. Floating point numbers are compared to
+// json.Number and math/big values by their shortest decimal representation,
+// so float64(0.1) equals json.Number("0.1"). Strings are never equal to
+// numbers.
+//
+// Maps are equal if they have the same set of keys and the values
+// associated with the keys are equal. A map with string key type and a
+// map with interface{} key type may be equal if the keys of the latter
+// are all strings.
+//
+// An OrderedMap is compared like a map with string key type (the order of
+// its keys is not compared).
+//
+// Slices are equal if they have the same length and their elements are
+// equal.
+//
+// Values of other types are compared using reflect.DeepEqual().
+func Equal(a, b interface{}) bool {
+	if m, ok := a.(*OrderedMap); ok && m != nil {
+		a = m.mapS()
+	}
+	if m, ok := b.(*OrderedMap); ok && m != nil {
+		b = m.mapS()
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		switch y := b.(type) {
+		case map[string]interface{}:
+			if len(x) != len(y) {
+				return false
+			}
+			for k, v := range x {
+				v2, ok := y[k]
+				if !ok || !Equal(v, v2) {
+					return false
+				}
+			}
+			return true
+		case map[interface{}]interface{}:
+			return equalMapSMapI(x, y)
+		}
+		return false
+
+	case map[interface{}]interface{}:
+		switch y := b.(type) {
+		case map[interface{}]interface{}:
+			if len(x) != len(y) {
+				return false
+			}
+			for k, v := range x {
+				v2, ok := y[k]
+				if !ok || !Equal(v, v2) {
+					return false
+				}
+			}
+			return true
+		case map[string]interface{}:
+			return equalMapSMapI(y, x)
+		}
+		return false
+
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i, v := range x {
+			if !Equal(v, y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if isNumber(a) && isNumber(b) {
+		c, ok := compareNumbers(a, b)
+		if ok {
+			return c == 0
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// equalMapSMapI tells if the map with string keys ms equals to the map with
+// interface{} keys mi.
+func equalMapSMapI(ms map[string]interface{}, mi map[interface{}]interface{}) bool {
+	if len(ms) != len(mi) {
+		return false
+	}
+	for k, v := range mi {
+		sk, ok := k.(string)
+		if !ok {
+			return false
+		}
+		v2, ok := ms[sk]
+		if !ok || !Equal(v2, v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package dyno
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	cases := []struct {
+		title string      // Title of the test case
+		a, b  interface{} // Input dynamic objects
+		exp   bool        // Expected result
+	}{
+		{
+			title: "nil values",
+			exp:   true,
+		},
+		{
+			title: "same strings",
+			a:     "a",
+			b:     "a",
+			exp:   true,
+		},
+		{
+			title: "string and number",
+			a:     "1",
+			b:     1,
+		},
+		{
+			title: "int and float64",
+			a:     1,
+			b:     1.0,
+			exp:   true,
+		},
+		{
+			title: "int and different float64",
+			a:     1,
+			b:     1.5,
+		},
+		{
+			title: "json.Number and uint8",
+			a:     json.Number("2.0"),
+			b:     uint8(2),
+			exp:   true,
+		},
+		{
+			title: "json.Numbers differing beyond float64 precision",
+			a:     json.Number("0.10000000000000000001"),
+			b:     json.Number("0.10000000000000000002"),
+		},
+		{
+			title: "json.Number and float64 differing beyond float64 precision",
+			a:     json.Number("0.10000000000000000001"),
+			b:     0.1,
+		},
+		{
+			title: "float64 and json.Number",
+			a:     0.1,
+			b:     json.Number("0.1"),
+			exp:   true,
+		},
+		{
+			title: "float32 and *big.Rat",
+			a:     float32(0.1),
+			b:     big.NewRat(1, 10),
+			exp:   true,
+		},
+		{
+			title: "large float64 and json.Number",
+			a:     float64(1 << 60),
+			b:     json.Number("1152921504606846976"),
+			exp:   true,
+		},
+		{
+			title: "*big.Int and json.Number",
+			a:     new(big.Int).Lsh(big.NewInt(1), 70),
+			b:     json.Number("1180591620717411303424"),
+			exp:   true,
+		},
+		{
+			title: "nested maps of different kinds",
+			a: map[string]interface{}{
+				"a": []interface{}{1, map[interface{}]interface{}{"b": 2.0}},
+			},
+			b: map[interface{}]interface{}{
+				"a": []interface{}{1.0, map[string]interface{}{"b": 2}},
+			},
+			exp: true,
+		},
+		{
+			title: "mi with non-string key and ms",
+			a:     map[string]interface{}{"1": 1},
+			b:     map[interface{}]interface{}{1: 1},
+		},
+		{
+			title: "maps with different keys",
+			a:     map[string]interface{}{"a": 1},
+			b:     map[string]interface{}{"b": 1},
+		},
+		{
+			title: "mi maps with different lengths",
+			a:     map[interface{}]interface{}{1: 1},
+			b:     map[interface{}]interface{}{1: 1, 2: 2},
+		},
+		{
+			title: "slices with different lengths",
+			a:     []interface{}{1},
+			b:     []interface{}{1, 2},
+		},
+		{
+			title: "slice and map",
+			a:     []interface{}{},
+			b:     map[string]interface{}{},
+		},
+		{
+			title: "other types",
+			a:     []string{"a"},
+			b:     []string{"a"},
+			exp:   true,
+		},
+	}
+
+	for _, c := range cases {
+		if got := Equal(c.a, c.b); got != c.exp {
+			t.Errorf("[title: %s] Expected: %v, got: %v", c.title, c.exp, got)
+		}
+		if got := Equal(c.b, c.a); got != c.exp {
+			t.Errorf("[title: %s] (reversed) Expected: %v, got: %v", c.title, c.exp, got)
+		}
+	}
+}
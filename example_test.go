@@ -232,3 +232,20 @@ func ExampleConvertMapI2MapS() {
 	// JSON: "", error: json: unsupported type: map[interface {}]interface {}
 	// JSON: {"1":"one","numbers":[2,3,4.4]}, error: <nil>
 }
+
+func ExampleSetAll() {
+	v := map[string]interface{}{
+		"plugins": []interface{}{
+			map[string]interface{}{"name": "a", "enabled": true},
+			map[string]interface{}{"name": "b"},
+		},
+	}
+
+	n, paths, err := dyno.SetAll(v, false, "plugins", dyno.Any, "enabled")
+	fmt.Println(n, paths, err)
+	json.NewEncoder(os.Stdout).Encode(v)
+
+	// Output:
+	// 2 [[plugins 0 enabled] [plugins 1 enabled]] <nil>
+	// {"plugins":[{"enabled":false,"name":"a"},{"enabled":false,"name":"b"}]}
+}
@@ -0,0 +1,67 @@
+package dyno
+
+// FindKey returns the concrete paths of all map elements (in maps of both
+// kinds and in Containers) in v whose key equals to key, at any depth.
+//
+// Map keys are visited in sorted order and slices in index order, so the
+// result is deterministic (parents come before their descendants).
+//
+// maxDepth limits the length of the returned paths; 0 or a negative value
+// means no limit.
+func FindKey(v interface{}, key interface{}, maxDepth int) [][]interface{} {
+	var paths [][]interface{}
+	find(v, nil, maxDepth, walkedPtrs{}, func(parent interface{}, path []interface{}) {
+		k := path[len(path)-1]
+		switch parent.(type) {
+		case map[string]interface{}, map[interface{}]interface{}, Container:
+			if k == key {
+				paths = append(paths, path)
+			}
+		}
+	})
+	return paths
+}
+
+// FindValue returns the concrete paths of all leaves in v (values that are
+// not maps and not slices) for which pred returns true, at any depth.
+//
+// Map keys are visited in sorted order and slices in index order, so the
+// result is deterministic.
+//
+// maxDepth limits the length of the returned paths; 0 or a negative value
+// means no limit.
+func FindValue(v interface{}, pred func(value interface{}) bool, maxDepth int) [][]interface{} {
+	var paths [][]interface{}
+	if !isContainer(v) && pred(v) {
+		paths = append(paths, []interface{}{})
+	}
+	find(v, nil, maxDepth, walkedPtrs{}, func(parent interface{}, path []interface{}) {
+		value, _, _ := getElem(parent, path[len(path)-1], len(path)-1)
+		if !isContainer(value) && pred(value) {
+			paths = append(paths, path)
+		}
+	})
+	return paths
+}
+
+// find walks node recursively, calling f for each child with its concrete
+// path, in deterministic order, up to maxDepth.
+func find(node interface{}, prefix []interface{}, maxDepth int, seen walkedPtrs, f func(parent interface{}, path []interface{})) {
+	if maxDepth > 0 && len(prefix) >= maxDepth {
+		return
+	}
+	walkChildren(node, prefix, seen, func(key, child interface{}, path []interface{}) (interface{}, error) {
+		f(node, path)
+		find(child, path, maxDepth, seen, f)
+		return child, nil
+	})
+}
+
+// isContainer tells if v is a map, a slice or a Container node.
+func isContainer(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}, Container:
+		return true
+	}
+	return false
+}
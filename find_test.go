@@ -0,0 +1,133 @@
+package dyno
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var findDoc = map[string]interface{}{
+	"token": "t1",
+	"auth": map[interface{}]interface{}{
+		"token": "t2",
+		1:       "one",
+	},
+	"list": []interface{}{
+		map[string]interface{}{"token": "t3", "user": "bob"},
+		"token",
+	},
+}
+
+func TestFindKey(t *testing.T) {
+	cases := []struct {
+		title    string          // Title of the test case
+		v        interface{}     // Input dynamic object
+		key      interface{}     // Key to find
+		maxDepth int             // Max depth
+		exp      [][]interface{} // Expected paths
+	}{
+		{
+			title: "string key in all map kinds",
+			v:     findDoc,
+			key:   "token",
+			exp: [][]interface{}{
+				{"auth", "token"},
+				{"list", 0, "token"},
+				{"token"},
+			},
+		},
+		{
+			title:    "with max depth",
+			v:        findDoc,
+			key:      "token",
+			maxDepth: 2,
+			exp: [][]interface{}{
+				{"auth", "token"},
+				{"token"},
+			},
+		},
+		{
+			title: "int key only in maps",
+			v:     findDoc,
+			key:   1,
+			exp:   [][]interface{}{{"auth", 1}},
+		},
+		{
+			title: "no match",
+			v:     findDoc,
+			key:   "x",
+		},
+		{
+			title: "non-container",
+			v:     "token",
+			key:   "token",
+		},
+	}
+
+	for _, c := range cases {
+		paths := FindKey(c.v, c.key, c.maxDepth)
+		if !reflect.DeepEqual(paths, c.exp) {
+			t.Errorf("[title: %s] Expected paths: %v, got: %v", c.title, c.exp, paths)
+		}
+	}
+}
+
+func TestFindValue(t *testing.T) {
+	isToken := func(v interface{}) bool {
+		s, ok := v.(string)
+		return ok && strings.HasPrefix(s, "t")
+	}
+
+	cases := []struct {
+		title    string                 // Title of the test case
+		v        interface{}            // Input dynamic object
+		pred     func(interface{}) bool // Predicate
+		maxDepth int                    // Max depth
+		exp      [][]interface{}        // Expected paths
+	}{
+		{
+			title: "matching leaves",
+			v:     findDoc,
+			pred:  isToken,
+			exp: [][]interface{}{
+				{"auth", "token"},
+				{"list", 0, "token"},
+				{"list", 1},
+				{"token"},
+			},
+		},
+		{
+			title:    "with max depth",
+			v:        findDoc,
+			pred:     isToken,
+			maxDepth: 1,
+			exp:      [][]interface{}{{"token"}},
+		},
+		{
+			title: "containers are not leaves",
+			v:     findDoc,
+			pred:  func(interface{}) bool { return true },
+			exp: [][]interface{}{
+				{"auth", 1},
+				{"auth", "token"},
+				{"list", 0, "token"},
+				{"list", 0, "user"},
+				{"list", 1},
+				{"token"},
+			},
+		},
+		{
+			title: "v itself is a leaf",
+			v:     "token",
+			pred:  isToken,
+			exp:   [][]interface{}{{}},
+		},
+	}
+
+	for _, c := range cases {
+		paths := FindValue(c.v, c.pred, c.maxDepth)
+		if !reflect.DeepEqual(paths, c.exp) {
+			t.Errorf("[title: %s] Expected paths: %v, got: %v", c.title, c.exp, paths)
+		}
+	}
+}
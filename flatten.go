@@ -0,0 +1,388 @@
+package dyno
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlattenOpts holds options for Flatten and Unflatten.
+type FlattenOpts struct {
+	// Separator of the path elements in flat keys. Defaults to ".".
+	Separator string
+
+	// Brackets tells to format slice indices in brackets, e.g. "a[0].b"
+	// instead of "a.0.b".
+	//
+	// When unflattening with Brackets, only bracketed segments are slice
+	// indices, else all numeric segments are (see Unflatten).
+	Brackets bool
+
+	// Escape tells to escape the separator, the backslash and (if Brackets
+	// is set) the brackets in map keys with a backslash, so keys containing
+	// them can be restored by Unflatten.
+	Escape bool
+
+	// EmptyContainers tells to include empty maps and slices as values,
+	// else they are omitted (only leaf values are included).
+	EmptyContainers bool
+}
+
+// separator returns the separator to use.
+func (o *FlattenOpts) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+// Flatten flattens the dynamic object v into a single-level map whose keys
+// are the paths of the leaf values (values other than maps and slices),
+// e.g. {"a": {"b": [1]}} is flattened to {"a.b.0": 1}. opts may be nil, in
+// which case the zero value of FlattenOpts is used.
+//
+// Keys of maps with interface{} key type are formatted using fmt.Sprint.
+// If v itself is a leaf value, the result has a single entry with the
+// empty key.
+//
+// An error is returned if multiple paths result in the same flat key
+// (e.g. {"a.b": 1, "a": {"b": 2}} without Escape).
+func Flatten(v interface{}, opts *FlattenOpts) (map[string]interface{}, error) {
+	if opts == nil {
+		opts = &FlattenOpts{}
+	}
+
+	res := map[string]interface{}{}
+	err := flatten(v, "", true, opts, res)
+	return res, err
+}
+
+// flatten adds the entries of node to res. prefix is the flat key of node,
+// root tells if node is the root.
+func flatten(node interface{}, prefix string, root bool, opts *FlattenOpts, res map[string]interface{}) error {
+	// Flat key of a child having the given key:
+	childKey := func(key string) string {
+		if opts.Escape {
+			key = escapeFlatKey(key, opts)
+		}
+		if root {
+			return key
+		}
+		return prefix + opts.separator() + key
+	}
+
+	var err error
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(n) == 0 {
+			break
+		}
+		for _, k := range sortedKeysS(n) {
+			if err = flatten(n[k], childKey(k), false, opts, res); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case map[interface{}]interface{}:
+		if len(n) == 0 {
+			break
+		}
+		for _, k := range sortedKeysI(n) {
+			if err = flatten(n[k], childKey(fmt.Sprint(k)), false, opts, res); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case []interface{}:
+		if len(n) == 0 {
+			break
+		}
+		for i, el := range n {
+			key := prefix + "[" + strconv.Itoa(i) + "]"
+			if !opts.Brackets {
+				key = childKey(strconv.Itoa(i))
+			}
+			if err = flatten(el, key, false, opts, res); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case Container:
+		if n.Len() == 0 {
+			break
+		}
+		return containerChildren(n, func(key, child interface{}) error {
+			return flatten(child, childKey(fmt.Sprint(key)), false, opts, res)
+		})
+	}
+
+	// Leaf or empty container:
+	if isContainer(node) && !opts.EmptyContainers {
+		return nil
+	}
+	if _, ok := res[prefix]; ok {
+		return fmt.Errorf("conflicting flat key: %q", prefix)
+	}
+	res[prefix] = node
+	return nil
+}
+
+// escapeFlatKey escapes the special characters in the map key k.
+func escapeFlatKey(k string, opts *FlattenOpts) string {
+	sep := opts.separator()
+	var b strings.Builder
+	for i := 0; i < len(k); {
+		switch {
+		case strings.HasPrefix(k[i:], sep):
+			b.WriteString(`\` + sep)
+			i += len(sep)
+			continue
+		case k[i] == '\\', opts.Brackets && (k[i] == '[' || k[i] == ']'):
+			b.WriteByte('\\')
+		}
+		b.WriteByte(k[i])
+		i++
+	}
+	return b.String()
+}
+
+// Unflatten is the inverse of Flatten: it rebuilds a nested dynamic object
+// from the single-level map flat. opts may be nil, in which case the zero
+// value of FlattenOpts is used; opts should be the same as used for
+// Flatten.
+//
+// Maps are created as map[string]interface{}. A map is turned into a
+// []interface{} if all its keys are slice indices: bracketed segments if
+// opts.Brackets is set, else non-negative decimal numbers (without leading
+// zeros). Missing slice elements are nil, but a slice may not miss more
+// elements than it has plus 16: such sparse indices are map keys (or an
+// error if opts.Brackets is set), so e.g. {"errors.404": x} is unflattened
+// to {"errors": {"404": x}}.
+//
+// Conflicting keys are errors, e.g. "a" having a leaf value along with
+// "a.b", or "a[0]" along with "a.b" (if opts.Brackets is set). An empty map
+// or slice value does not conflict with keys below it.
+//
+// The empty key denotes the root, so {"": v} is unflattened to v.
+func Unflatten(flat map[string]interface{}, opts *FlattenOpts) (interface{}, error) {
+	if opts == nil {
+		opts = &FlattenOpts{}
+	}
+
+	root := &flatNode{}
+	for key, value := range flat {
+		segs, err := parseFlatKey(key, opts)
+		if err != nil {
+			return nil, err
+		}
+		n := root
+		for _, seg := range segs {
+			n = n.child(seg)
+		}
+		n.value, n.hasValue = value, true
+	}
+
+	return root.build("", opts)
+}
+
+// flatSeg is a segment of a flat key.
+type flatSeg struct {
+	key   string // Map key or slice index
+	index bool   // Tells if the segment is a bracketed slice index
+}
+
+// parseFlatKey splits the flat key into segments.
+func parseFlatKey(key string, opts *FlattenOpts) ([]flatSeg, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	sep := opts.separator()
+	var segs []flatSeg
+	var cur strings.Builder
+	// Tells if the current segment has to be added (an index segment
+	// just closed is not followed by an empty map key):
+	pending := true
+
+	for i := 0; i < len(key); {
+		switch {
+		case opts.Escape && key[i] == '\\':
+			i++
+			if i == len(key) {
+				return nil, fmt.Errorf("invalid escape at end of flat key: %q", key)
+			}
+			if strings.HasPrefix(key[i:], sep) {
+				cur.WriteString(sep)
+				i += len(sep)
+			} else {
+				cur.WriteByte(key[i])
+				i++
+			}
+			pending = true
+
+		case strings.HasPrefix(key[i:], sep):
+			if pending {
+				segs = append(segs, flatSeg{key: cur.String()})
+			}
+			cur.Reset()
+			pending = true
+			i += len(sep)
+
+		case opts.Brackets && key[i] == '[':
+			end := strings.IndexByte(key[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' in flat key: %q", key)
+			}
+			idx := key[i+1 : i+end]
+			if !isIndexSeg(idx) {
+				return nil, fmt.Errorf("invalid index %q in flat key: %q", idx, key)
+			}
+			if pending && i > 0 {
+				segs = append(segs, flatSeg{key: cur.String()})
+			}
+			segs = append(segs, flatSeg{key: idx, index: true})
+			cur.Reset()
+			pending = false
+			i += end + 1
+
+		default:
+			cur.WriteByte(key[i])
+			pending = true
+			i++
+		}
+	}
+	if pending {
+		segs = append(segs, flatSeg{key: cur.String()})
+	}
+
+	return segs, nil
+}
+
+// isIndexSeg tells if s is a valid slice index: a non-negative decimal
+// number without leading zeros (having at most 9 digits).
+func isIndexSeg(s string) bool {
+	if s == "" || len(s) > 9 || len(s) > 1 && s[0] == '0' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// maxFlatGap is the number of missing elements allowed in a slice built by
+// Unflatten beyond the number of its present elements, so a few flat keys
+// cannot allocate huge slices.
+const maxFlatGap = 16
+
+// flatNode is a node of the tree built by Unflatten.
+type flatNode struct {
+	value    interface{}           // Value of the node
+	hasValue bool                  // Tells if the node has a value
+	children map[flatSeg]*flatNode // Child nodes
+}
+
+// child returns the child node denoted by seg, creating it if needed.
+func (n *flatNode) child(seg flatSeg) *flatNode {
+	if n.children == nil {
+		n.children = map[flatSeg]*flatNode{}
+	}
+	c := n.children[seg]
+	if c == nil {
+		c = &flatNode{}
+		n.children[seg] = c
+	}
+	return c
+}
+
+// build builds the dynamic object from the node. key is the flat key of
+// the node (used in error messages).
+func (n *flatNode) build(key string, opts *FlattenOpts) (interface{}, error) {
+	if len(n.children) == 0 {
+		if !n.hasValue {
+			return map[string]interface{}{}, nil
+		}
+		return n.value, nil
+	}
+	if n.hasValue && !isEmptyContainer(n.value) {
+		return nil, fmt.Errorf("conflicting flat keys: %q has a value and also nested keys", key)
+	}
+
+	// Decide if this is a slice:
+	var indices, keys, max int
+	for seg := range n.children {
+		if seg.index || !opts.Brackets && isIndexSeg(seg.key) {
+			indices++
+			if idx, _ := strconv.Atoi(seg.key); idx > max {
+				max = idx
+			}
+		} else {
+			keys++
+		}
+	}
+	if opts.Brackets && indices > 0 && keys > 0 {
+		return nil, fmt.Errorf("conflicting flat keys: %q has both slice indices and map keys", key)
+	}
+	if keys == 0 && max+1-indices > indices+maxFlatGap {
+		if opts.Brackets {
+			return nil, fmt.Errorf("sparse slice indices: %q has %d elements, max index: %d", key, indices, max)
+		}
+		keys = indices // Too sparse, build a map
+	}
+
+	childKey := func(seg flatSeg) string {
+		if seg.index {
+			return key + "[" + seg.key + "]"
+		}
+		k := seg.key
+		if opts.Escape {
+			k = escapeFlatKey(k, opts)
+		}
+		if key == "" {
+			return k
+		}
+		return key + opts.separator() + k
+	}
+
+	if keys == 0 {
+		// Slice:
+		s := make([]interface{}, max+1)
+		for seg, c := range n.children {
+			idx, _ := strconv.Atoi(seg.key)
+			v, err := c.build(childKey(seg), opts)
+			if err != nil {
+				return nil, err
+			}
+			s[idx] = v
+		}
+		return s, nil
+	}
+
+	m := make(map[string]interface{}, len(n.children))
+	for seg, c := range n.children {
+		v, err := c.build(childKey(seg), opts)
+		if err != nil {
+			return nil, err
+		}
+		m[seg.key] = v
+	}
+	return m, nil
+}
+
+// isEmptyContainer tells if v is an empty map or slice.
+func isEmptyContainer(v interface{}) bool {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return len(x) == 0
+	case map[interface{}]interface{}:
+		return len(x) == 0
+	case []interface{}:
+		return len(x) == 0
+	}
+	return false
+}
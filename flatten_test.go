@@ -0,0 +1,195 @@
+package dyno
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	cases := []struct {
+		title string                 // Title of the test case
+		v     string                 // Input dynamic object in JSON
+		opts  *FlattenOpts           // Options
+		exp   map[string]interface{} // Expected result
+		isErr bool                   // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "default options",
+			v:     `{"a": {"b": [{"c": 1}, 2]}, "d": "x", "e": {}, "f": []}`,
+			exp:   map[string]interface{}{"a.b.0.c": 1.0, "a.b.1": 2.0, "d": "x"},
+		},
+		{
+			title: "brackets and separator",
+			v:     `{"a": {"b": [{"c": 1}, [2]]}}`,
+			opts:  &FlattenOpts{Separator: "/", Brackets: true},
+			exp:   map[string]interface{}{"a/b[0]/c": 1.0, "a/b[1][0]": 2.0},
+		},
+		{
+			title: "empty containers",
+			v:     `{"a": {}, "b": [], "c": [{}]}`,
+			opts:  &FlattenOpts{EmptyContainers: true},
+			exp: map[string]interface{}{
+				"a": map[string]interface{}{}, "b": []interface{}{}, "c.0": map[string]interface{}{},
+			},
+		},
+		{
+			title: "escaping",
+			v:     `{"a.b": {"c\\d": 1, "e[0]": 2}}`,
+			opts:  &FlattenOpts{Escape: true, Brackets: true},
+			exp:   map[string]interface{}{`a\.b.c\\d`: 1.0, `a\.b.e\[0\]`: 2.0},
+		},
+		{
+			title: "leaf root",
+			v:     `1`,
+			exp:   map[string]interface{}{"": 1.0},
+		},
+		{
+			title: "root slice",
+			v:     `[1, 2]`,
+			opts:  &FlattenOpts{Brackets: true},
+			exp:   map[string]interface{}{"[0]": 1.0, "[1]": 2.0},
+		},
+
+		// Test errors:
+		{
+			title: "conflicting keys error",
+			v:     `{"a.b": 1, "a": {"b": 2}}`,
+			exp:   map[string]interface{}{"a.b": 2.0},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		res, err := Flatten(decodeJSON(c.v), c.opts)
+		if !reflect.DeepEqual(res, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, res)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	// Keys of maps with interface{} key type:
+	res, err := Flatten(map[interface{}]interface{}{1: map[interface{}]interface{}{true: "x"}}, nil)
+	if exp := map[string]interface{}{"1.true": "x"}; !reflect.DeepEqual(res, exp) || err != nil {
+		t.Errorf("Expected value: %v, got: %v, err value: %v", exp, res, err)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	cases := []struct {
+		title string                 // Title of the test case
+		flat  map[string]interface{} // Input flat map
+		opts  *FlattenOpts           // Options
+		exp   string                 // Expected result in JSON
+		isErr bool                   // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "default options",
+			flat:  map[string]interface{}{"a.b.0.c": 1, "a.b.2": 2, "a.01": 3, "d": "x"},
+			exp:   `{"a": {"b": [{"c": 1}, null, 2], "01": 3}, "d": "x"}`,
+		},
+		{
+			title: "brackets and separator",
+			flat:  map[string]interface{}{"a/b[0]/c": 1, "a/b[1][0]": 2, "a/0": 3},
+			opts:  &FlattenOpts{Separator: "/", Brackets: true},
+			exp:   `{"a": {"b": [{"c": 1}, [2]], "0": 3}}`,
+		},
+		{
+			title: "empty containers",
+			flat:  map[string]interface{}{"a": map[string]interface{}{}, "b": []interface{}{}, "b.0": 1},
+			exp:   `{"a": {}, "b": [1]}`,
+		},
+		{
+			title: "escaping",
+			flat:  map[string]interface{}{`a\.b.c\\d`: 1, `a\.b.e\[0\]`: 2},
+			opts:  &FlattenOpts{Escape: true, Brackets: true},
+			exp:   `{"a.b": {"c\\d": 1, "e[0]": 2}}`,
+		},
+		{
+			title: "root",
+			flat:  map[string]interface{}{"": 1},
+			exp:   `1`,
+		},
+		{
+			title: "root slice",
+			flat:  map[string]interface{}{"[1]": 1},
+			opts:  &FlattenOpts{Brackets: true},
+			exp:   `[null, 1]`,
+		},
+		{
+			title: "sparse indices",
+			flat:  map[string]interface{}{"errors.404": "x", "a.99999999": 1, "b.0": 1, "b.17": 2},
+			exp:   `{"errors": {"404": "x"}, "a": {"99999999": 1}, "b": [1, null, null, null, null, null, null, null, null, null, null, null, null, null, null, null, null, 2]}`,
+		},
+		{
+			title: "empty",
+			flat:  map[string]interface{}{},
+			exp:   `{}`,
+		},
+
+		// Test errors:
+		{
+			title: "value and nested keys error",
+			flat:  map[string]interface{}{"a": 1, "a.b": 2},
+			isErr: true,
+		},
+		{
+			title: "index and map key error",
+			flat:  map[string]interface{}{"a[0]": 1, "a.b": 2},
+			opts:  &FlattenOpts{Brackets: true},
+			isErr: true,
+		},
+		{
+			title: "invalid index error",
+			flat:  map[string]interface{}{"a[x]": 1},
+			opts:  &FlattenOpts{Brackets: true},
+			isErr: true,
+		},
+		{
+			title: "sparse indices error",
+			flat:  map[string]interface{}{"a[99999999]": 1},
+			opts:  &FlattenOpts{Brackets: true},
+			isErr: true,
+		},
+		{
+			title: "invalid escape error",
+			flat:  map[string]interface{}{`a\`: 1},
+			opts:  &FlattenOpts{Escape: true},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		res, err := Unflatten(c.flat, c.opts)
+		if !c.isErr && !Equal(res, decodeJSON(c.exp)) {
+			got, _ := json.Marshal(res)
+			t.Errorf("[title: %s] Expected value: %s, got: %s", c.title, c.exp, got)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	src := `{"a.b": {"x[1]": [1, {"c/d": [true, null]}]}, "e": "f"}`
+	for _, opts := range []*FlattenOpts{
+		{Escape: true},
+		{Escape: true, Brackets: true},
+		{Escape: true, Brackets: true, Separator: "/"},
+	} {
+		flat, err := Flatten(decodeJSON(src), opts)
+		if err != nil {
+			t.Errorf("[opts: %+v] Unexpected error: %v", opts, err)
+		}
+		res, err := Unflatten(flat, opts)
+		if !Equal(res, decodeJSON(src)) || err != nil {
+			got, _ := json.Marshal(res)
+			t.Errorf("[opts: %+v] Expected value: %s, got: %s, err value: %v", opts, src, got, err)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package dyno
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Match tells if the dynamic object doc matches filter, a MongoDB-style
+// query-by-example document (which itself is a dynamic object, e.g.
+// unmarshaled from JSON).
+//
+// Keys of filter are field paths where path elements are separated by dots,
+// e.g. "address.city" or "tags.0". Field paths are resolved using the rules
+// of Get: map keys and slice indices (given as decimal numbers). Values of
+// filter are either values to compare with using Equal, or operator
+// expressions (maps whose keys are operators). All keys of the filter must
+// match.
+//
+// Supported field operators:
+//   -$eq, $ne: equality (as defined by Equal)
+//   -$gt, $gte, $lt, $lte: ordering of numbers (by value), strings and time.Time values
+//   -$in, $nin: the value is (not) equal to any element of the given slice
+//   -$exists: the field does (not) exist
+//   -$regex: the value is a string matching the given regular expression
+//    ($options may contain "i" for case-insensitive matching)
+//   -$not: negates the given operator expression
+//   -$elemMatch: the value is a slice having an element that matches the
+//    given filter (or operator expression)
+//
+// Supported top-level operators: $and, $or, $nor, whose values are slices
+// of filters.
+//
+// Just like in MongoDB, if a field value is a slice, equality and
+// comparison operators match if the slice itself or any of its elements
+// matches.
+//
+// An error is returned if filter is invalid (e.g. has an unknown operator).
+func Match(doc interface{}, filter interface{}) (bool, error) {
+	f, err := filterMap(filter)
+	if err != nil {
+		return false, err
+	}
+
+	for _, key := range sortedKeysS(f) {
+		cond := f[key]
+
+		var ok bool
+		switch key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(doc, key, cond)
+		default:
+			if strings.HasPrefix(key, "$") {
+				return false, fmt.Errorf("unknown top-level operator: %s", key)
+			}
+			value, exists := getField(doc, key)
+			ok, err = matchCond(value, exists, cond)
+		}
+
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Filter returns the elements of slice that match filter.
+// See Match for the filter syntax.
+func Filter(slice []interface{}, filter interface{}) ([]interface{}, error) {
+	var res []interface{}
+	for _, el := range slice {
+		ok, err := Match(el, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			res = append(res, el)
+		}
+	}
+	return res, nil
+}
+
+// filterMap returns filter as a map with string keys.
+func filterMap(filter interface{}) (map[string]interface{}, error) {
+	switch f := filter.(type) {
+	case map[string]interface{}:
+		return f, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(f))
+		for k, v := range f {
+			m[fmt.Sprint(k)] = v
+		}
+		return m, nil
+	case *OrderedMap:
+		return f.mapS(), nil
+	}
+	return nil, fmt.Errorf("expected map filter, got: %T", filter)
+}
+
+// getField returns the value denoted by the dotted field path.
+func getField(doc interface{}, field string) (interface{}, bool) {
+	path, err := fieldPath(doc, field, false, nil)
+	if err != nil {
+		return nil, false
+	}
+	v, err := Get(doc, path...)
+	return v, err == nil
+}
+
+// matchLogical evaluates the $and, $or and $nor operators.
+func matchLogical(doc interface{}, op string, cond interface{}) (bool, error) {
+	filters, ok := cond.([]interface{})
+	if !ok {
+		return false, fmt.Errorf("expected slice for %s, got: %T", op, cond)
+	}
+
+	for _, f := range filters {
+		ok, err := Match(doc, f)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case op == "$and" && !ok:
+			return false, nil
+		case op == "$or" && ok:
+			return true, nil
+		case op == "$nor" && ok:
+			return false, nil
+		}
+	}
+	return op != "$or", nil
+}
+
+// isOperatorExpr tells if cond is an operator expression: a non-empty map
+// whose keys all start with "$".
+func isOperatorExpr(cond interface{}) (map[string]interface{}, bool) {
+	m, err := filterMap(cond)
+	if err != nil || len(m) == 0 {
+		return nil, false
+	}
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+// matchCond tells if value matches the condition cond.
+func matchCond(value interface{}, exists bool, cond interface{}) (bool, error) {
+	ops, ok := isOperatorExpr(cond)
+	if !ok {
+		return exists && matchAny(value, func(v interface{}) bool { return Equal(v, cond) }), nil
+	}
+
+	for _, op := range sortedKeysS(ops) {
+		ok, err := matchOp(value, exists, op, ops[op], ops)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchOp tells if value matches the operator op with argument arg.
+// ops is the operator expression op is part of.
+func matchOp(value interface{}, exists bool, op string, arg interface{}, ops map[string]interface{}) (bool, error) {
+	switch op {
+	case "$eq":
+		return exists && matchAny(value, func(v interface{}) bool { return Equal(v, arg) }), nil
+
+	case "$ne":
+		return !exists || !matchAny(value, func(v interface{}) bool { return Equal(v, arg) }), nil
+
+	case "$gt", "$gte", "$lt", "$lte":
+		return exists && matchAny(value, func(v interface{}) bool {
+			c, ok := compareValues(v, arg)
+			if !ok {
+				return false
+			}
+			switch op {
+			case "$gt":
+				return c > 0
+			case "$gte":
+				return c >= 0
+			case "$lt":
+				return c < 0
+			}
+			return c <= 0
+		}), nil
+
+	case "$in", "$nin":
+		list, ok := arg.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("expected slice for %s, got: %T", op, arg)
+		}
+		in := exists && matchAny(value, func(v interface{}) bool {
+			for _, el := range list {
+				if Equal(v, el) {
+					return true
+				}
+			}
+			return false
+		})
+		return in == (op == "$in"), nil
+
+	case "$exists":
+		want, err := GetBoolean(arg)
+		if err != nil {
+			return false, fmt.Errorf("invalid $exists argument: %v", err)
+		}
+		return exists == want, nil
+
+	case "$regex":
+		re, err := compileRegex(arg, ops["$options"])
+		if err != nil {
+			return false, err
+		}
+		return exists && matchAny(value, func(v interface{}) bool {
+			s, ok := v.(string)
+			return ok && re.MatchString(s)
+		}), nil
+
+	case "$options":
+		if _, ok := ops["$regex"]; !ok {
+			return false, fmt.Errorf("$options without $regex")
+		}
+		return true, nil
+
+	case "$not":
+		var ok bool
+		var err error
+		if _, isExpr := isOperatorExpr(arg); isExpr {
+			ok, err = matchCond(value, exists, arg)
+		} else {
+			ok, err = matchOp(value, exists, "$regex", arg, nil)
+		}
+		return !ok, err
+
+	case "$elemMatch":
+		s, ok := value.([]interface{})
+		if !exists || !ok {
+			return false, nil
+		}
+		_, isExpr := isOperatorExpr(arg)
+		for _, el := range s {
+			var ok bool
+			var err error
+			if isExpr {
+				ok, err = matchCond(el, true, arg)
+			} else {
+				ok, err = Match(el, arg)
+			}
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return false, fmt.Errorf("unknown operator: %s", op)
+}
+
+// matchAny tells if value or any of its elements (if it is a slice)
+// satisfies f.
+func matchAny(value interface{}, f func(v interface{}) bool) bool {
+	if f(value) {
+		return true
+	}
+	if s, ok := value.([]interface{}); ok {
+		for _, el := range s {
+			if f(el) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareValues compares numbers (by value), strings and time.Time values.
+// The second return value tells if a and b are comparable.
+func compareValues(a, b interface{}) (int, bool) {
+	if isNumber(a) && isNumber(b) {
+		return compareNumbers(a, b)
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1, true
+			case x.After(y):
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+// compileRegex compiles the $regex pattern with the optional $options.
+func compileRegex(pattern, options interface{}) (*regexp.Regexp, error) {
+	if re, ok := pattern.(*regexp.Regexp); ok {
+		return re, nil
+	}
+
+	s, ok := pattern.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected string for $regex, got: %T", pattern)
+	}
+	if options != nil {
+		opts, ok := options.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string for $options, got: %T", options)
+		}
+		var flags string
+		for _, o := range opts {
+			switch o {
+			case 'i', 'm', 's':
+				flags += string(o)
+			default:
+				return nil, fmt.Errorf("unsupported $options flag: %c", o)
+			}
+		}
+		if flags != "" {
+			s = "(?" + flags + ")" + s
+		}
+	}
+
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid $regex: %v", err)
+	}
+	return re, nil
+}
@@ -0,0 +1,118 @@
+package dyno
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// decodeJSON unmarshals src into a dynamic object, panics on error.
+func decodeJSON(src string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestMatch(t *testing.T) {
+	doc := decodeJSON(`{
+		"name": "bob",
+		"age": 22,
+		"address": {"city": "NYC", "zip": "10001"},
+		"tags": ["admin", "dev"],
+		"scores": [{"k": "a", "v": 5}, {"k": "b", "v": 9}]
+	}`)
+
+	cases := []struct {
+		title  string // Title of the test case
+		filter string // Filter in JSON
+		exp    bool   // Expected result
+		isErr  bool   // Tells if error is expected
+	}{
+		// Test success:
+		{title: "empty filter", filter: `{}`, exp: true},
+		{title: "equality", filter: `{"name": "bob"}`, exp: true},
+		{title: "equality mismatch", filter: `{"name": "alice"}`},
+		{title: "dotted path", filter: `{"address.city": "NYC"}`, exp: true},
+		{title: "slice index path", filter: `{"scores.1.k": "b"}`, exp: true},
+		{title: "equality on slice element", filter: `{"tags": "dev"}`, exp: true},
+		{title: "equality on whole slice", filter: `{"tags": ["admin", "dev"]}`, exp: true},
+		{title: "missing field", filter: `{"x": null}`},
+		{title: "$eq", filter: `{"age": {"$eq": 22.0}}`, exp: true},
+		{title: "$ne", filter: `{"age": {"$ne": 22}}`},
+		{title: "$ne on missing field", filter: `{"x": {"$ne": 1}}`, exp: true},
+		{title: "$gt and $lt", filter: `{"age": {"$gt": 18, "$lt": 30}}`, exp: true},
+		{title: "$gte and $lte", filter: `{"age": {"$gte": 22, "$lte": 22}}`, exp: true},
+		{title: "$gt mismatch", filter: `{"age": {"$gt": 22}}`},
+		{title: "$gt on strings", filter: `{"name": {"$gt": "alice"}}`, exp: true},
+		{title: "$gt on different types", filter: `{"name": {"$gt": 1}}`},
+		{title: "$in", filter: `{"name": {"$in": ["alice", "bob"]}}`, exp: true},
+		{title: "$in on slice", filter: `{"tags": {"$in": ["x", "dev"]}}`, exp: true},
+		{title: "$nin", filter: `{"name": {"$nin": ["alice", "bob"]}}`},
+		{title: "$exists true", filter: `{"address.zip": {"$exists": true}}`, exp: true},
+		{title: "$exists false", filter: `{"address.street": {"$exists": false}}`, exp: true},
+		{title: "$regex", filter: `{"name": {"$regex": "^B", "$options": "i"}}`, exp: true},
+		{title: "$regex mismatch", filter: `{"name": {"$regex": "^B"}}`},
+		{title: "$not", filter: `{"age": {"$not": {"$gt": 30}}}`, exp: true},
+		{title: "$not with regex", filter: `{"name": {"$not": "^b"}}`},
+		{title: "$elemMatch", filter: `{"scores": {"$elemMatch": {"k": "b", "v": {"$gt": 8}}}}`, exp: true},
+		{title: "$elemMatch mismatch", filter: `{"scores": {"$elemMatch": {"k": "a", "v": {"$gt": 8}}}}`},
+		{title: "$elemMatch with operator expression", filter: `{"tags": {"$elemMatch": {"$regex": "^d"}}}`, exp: true},
+		{title: "$and", filter: `{"$and": [{"name": "bob"}, {"age": 22}]}`, exp: true},
+		{title: "$or", filter: `{"$or": [{"name": "alice"}, {"age": 22}]}`, exp: true},
+		{title: "$or mismatch", filter: `{"$or": [{"name": "alice"}, {"age": 23}]}`},
+		{title: "$nor", filter: `{"$nor": [{"name": "alice"}, {"age": 23}]}`, exp: true},
+
+		// Test errors:
+		{title: "unknown operator error", filter: `{"age": {"$foo": 1}}`, isErr: true},
+		{title: "unknown top-level operator error", filter: `{"$foo": 1}`, isErr: true},
+		{title: "$in with non-slice error", filter: `{"age": {"$in": 1}}`, isErr: true},
+		{title: "$and with non-slice error", filter: `{"$and": {}}`, isErr: true},
+		{title: "invalid $regex error", filter: `{"name": {"$regex": "("}}`, isErr: true},
+		{title: "$options without $regex error", filter: `{"name": {"$options": "i"}}`, isErr: true},
+		{title: "non-map filter error", filter: `[]`, isErr: true},
+	}
+
+	for _, c := range cases {
+		ok, err := Match(doc, decodeJSON(c.filter))
+		if ok != c.exp {
+			t.Errorf("[title: %s] Expected: %v, got: %v", c.title, c.exp, ok)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestMatchMapI(t *testing.T) {
+	doc := map[interface{}]interface{}{
+		"a": map[interface{}]interface{}{1: "one"},
+	}
+	filter := map[interface{}]interface{}{"a.1": "one"}
+	if ok, err := Match(doc, filter); !ok || err != nil {
+		t.Errorf("Expected match, got: %v, err value: %v", ok, err)
+	}
+}
+
+func TestMatchOrdered(t *testing.T) {
+	doc := mustOrdered(`{"a": {"b": 1}, "c": [1, 2]}`)
+	filter := mustOrdered(`{"a.b": 1, "c": {"$exists": true}}`)
+	if ok, err := Match(doc, filter); !ok || err != nil {
+		t.Errorf("Expected match, got: %v, err value: %v", ok, err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	records := decodeJSON(`[{"n": 1}, {"n": 2}, {"n": 3}, "x"]`).([]interface{})
+
+	res, err := Filter(records, decodeJSON(`{"n": {"$gte": 2}}`))
+	if exp := records[1:3]; !reflect.DeepEqual(res, exp) || err != nil {
+		t.Errorf("Expected: %v, got: %v, err value: %v", exp, res, err)
+	}
+
+	res, err = Filter(records, decodeJSON(`{"n": {"$bad": 2}}`))
+	if res != nil || err == nil {
+		t.Errorf("Expected error, got: %v, err value: %v", res, err)
+	}
+}
@@ -0,0 +1,330 @@
+package dyno
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// OrderedMap is a map with string keys that preserves the insertion order
+// of its keys. It implements Container and Cloner, so it can be used in all
+// operations of this package just like map[string]interface{}, and walkers
+// visit its keys in order.
+//
+// Its JSON encoding lists keys in order, see MarshalJSON. To decode JSON
+// objects into OrderedMap values, use DecodeOrdered or UnmarshalOrdered.
+//
+// The zero value is an empty map ready to use. OrderedMap values should be
+// used via pointers. A nil *OrderedMap is treated as an empty map by read
+// operations.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedMap returns a new, empty OrderedMap.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{}
+}
+
+// Get returns the value associated with key, and whether key exists.
+func (m *OrderedMap) Get(key string) (value interface{}, exists bool) {
+	if m == nil {
+		return nil, false
+	}
+	value, exists = m.values[key]
+	return
+}
+
+// Set associates value with key. New keys are added to the end, existing
+// keys keep their positions.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = map[string]interface{}{}
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Delete deletes key, and reports whether it existed.
+func (m *OrderedMap) Delete(key string) bool {
+	i := m.index(key)
+	if i < 0 {
+		return false
+	}
+	m.keys = append(m.keys[:i], m.keys[i+1:]...)
+	delete(m.values, key)
+	return true
+}
+
+// Keys returns the keys in order. The returned slice is a copy.
+func (m *OrderedMap) Keys() []string {
+	if m == nil {
+		return nil
+	}
+	return append([]string(nil), m.keys...)
+}
+
+// Len returns the number of keys.
+func (m *OrderedMap) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.keys)
+}
+
+// mapS returns the entries of m as a map[string]interface{} (sharing the
+// underlying map of m).
+func (m *OrderedMap) mapS() map[string]interface{} {
+	if m == nil || m.values == nil {
+		return map[string]interface{}{}
+	}
+	return m.values
+}
+
+// index returns the index of key in m.keys, -1 if key does not exist.
+func (m *OrderedMap) index(key string) int {
+	if _, ok := m.Get(key); !ok {
+		return -1
+	}
+	for i, k := range m.keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// MoveToFront moves key to the front, and reports whether it exists.
+func (m *OrderedMap) MoveToFront(key string) bool {
+	return m.move(key, 0)
+}
+
+// MoveToBack moves key to the back, and reports whether it exists.
+func (m *OrderedMap) MoveToBack(key string) bool {
+	return m.move(key, m.Len()-1)
+}
+
+// MoveBefore moves key right before mark, and reports whether both exist.
+func (m *OrderedMap) MoveBefore(key, mark string) bool {
+	i, j := m.index(key), m.index(mark)
+	if i < 0 || j < 0 {
+		return false
+	}
+	if i < j {
+		j--
+	}
+	return m.move(key, j)
+}
+
+// MoveAfter moves key right after mark, and reports whether both exist.
+func (m *OrderedMap) MoveAfter(key, mark string) bool {
+	i, j := m.index(key), m.index(mark)
+	if i < 0 || j < 0 {
+		return false
+	}
+	if i > j {
+		j++
+	}
+	return m.move(key, j)
+}
+
+// move moves key to the index to (in the key order after the move), and
+// reports whether key exists.
+func (m *OrderedMap) move(key string, to int) bool {
+	i := m.index(key)
+	if i < 0 {
+		return false
+	}
+	if i < to {
+		copy(m.keys[i:to], m.keys[i+1:to+1])
+	} else {
+		copy(m.keys[to+1:i+1], m.keys[to:i])
+	}
+	m.keys[to] = key
+	return true
+}
+
+// SortKeys sorts the keys using less (stable sort). If less is nil, keys
+// are sorted lexicographically.
+func (m *OrderedMap) SortKeys(less func(a, b string) bool) {
+	if less == nil {
+		less = func(a, b string) bool { return a < b }
+	}
+	sort.SliceStable(m.keys, func(i, j int) bool {
+		return less(m.keys[i], m.keys[j])
+	})
+}
+
+// GetElem implements Container. key must be a string.
+func (m *OrderedMap) GetElem(key interface{}) (value interface{}, exists bool, err error) {
+	k, ok := key.(string)
+	if !ok {
+		return nil, false, fmt.Errorf("expected string path element, got: %T", key)
+	}
+	value, exists = m.Get(k)
+	return value, exists, nil
+}
+
+// SetElem implements Container. key must be a string.
+func (m *OrderedMap) SetElem(key, value interface{}) error {
+	k, ok := key.(string)
+	if !ok {
+		return fmt.Errorf("expected string path element, got: %T", key)
+	}
+	m.Set(k, value)
+	return nil
+}
+
+// DeleteElem implements Container. Deleting a missing key is not an error
+// (like with the builtin delete()).
+func (m *OrderedMap) DeleteElem(key interface{}) error {
+	if k, ok := key.(string); ok {
+		m.Delete(k)
+	}
+	return nil
+}
+
+// Range implements Container, f is called with the keys in order.
+func (m *OrderedMap) Range(f func(key, value interface{}) bool) {
+	if m == nil {
+		return
+	}
+	for _, k := range m.keys {
+		if !f(k, m.values[k]) {
+			return
+		}
+	}
+}
+
+// Clone implements Cloner.
+func (m *OrderedMap) Clone() Container {
+	if m == nil {
+		return m
+	}
+	c := &OrderedMap{
+		keys:   m.Keys(),
+		values: make(map[string]interface{}, len(m.values)),
+	}
+	for k, v := range m.values {
+		c.values[k] = v
+	}
+	return c
+}
+
+// MarshalJSON implements json.Marshaler, keys are written in order.
+// Maps with interface{} keys in values are converted like
+// ConvertMapI2MapS does (without modifying them).
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		data, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+		buf.WriteByte(':')
+		if data, err = json.Marshal(jsonCompatible(m.values[k])); err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the JSON data must be an
+// object. Nested objects are decoded into OrderedMap values too, see
+// DecodeOrdered.
+func (m *OrderedMap) UnmarshalJSON(data []byte) error {
+	v, err := UnmarshalOrdered(data)
+	if err != nil {
+		return err
+	}
+	om, ok := v.(*OrderedMap)
+	if !ok {
+		return fmt.Errorf("expected JSON object, got: %T", v)
+	}
+	*m = *om
+	return nil
+}
+
+// DecodeOrdered reads the next JSON value from dec. JSON objects are
+// decoded into *OrderedMap values (instead of map[string]interface{}),
+// arrays into []interface{} values, other values like dec.Decode() would
+// decode them into an interface{} (so dec.UseNumber() is honored).
+//
+// If an object has duplicate keys, the last value wins, at the position of
+// the first key.
+func DecodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		m := NewOrderedMap()
+		for dec.More() {
+			if tok, err = dec.Token(); err != nil {
+				return nil, err
+			}
+			key, ok := tok.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected object key, got: %v", tok)
+			}
+			value, err := DecodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(key, value)
+		}
+		_, err = dec.Token() // Closing '}'
+		return m, err
+
+	case '[':
+		s := []interface{}{}
+		for dec.More() {
+			value, err := DecodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, value)
+		}
+		_, err = dec.Token() // Closing ']'
+		return s, err
+	}
+
+	return nil, fmt.Errorf("unexpected delimiter: %v", delim)
+}
+
+// UnmarshalOrdered parses the JSON data like DecodeOrdered does. The data
+// must hold exactly one JSON value.
+func UnmarshalOrdered(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	v, err := DecodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid data after top-level value")
+	}
+	return v, nil
+}
@@ -0,0 +1,185 @@
+package dyno
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOrderedMapMove(t *testing.T) {
+	cases := []struct {
+		title string                   // Title of the test case
+		move  func(m *OrderedMap) bool // Move operation
+		keys  string                   // Expected keys (comma separated)
+		ok    bool                     // Expected result of the move
+	}{
+		{"front", func(m *OrderedMap) bool { return m.MoveToFront("c") }, "c,a,b,d", true},
+		{"back", func(m *OrderedMap) bool { return m.MoveToBack("a") }, "b,c,d,a", true},
+		{"before forward", func(m *OrderedMap) bool { return m.MoveBefore("a", "d") }, "b,c,a,d", true},
+		{"before backward", func(m *OrderedMap) bool { return m.MoveBefore("d", "b") }, "a,d,b,c", true},
+		{"after forward", func(m *OrderedMap) bool { return m.MoveAfter("a", "c") }, "b,c,a,d", true},
+		{"after backward", func(m *OrderedMap) bool { return m.MoveAfter("d", "a") }, "a,d,b,c", true},
+		{"self", func(m *OrderedMap) bool { return m.MoveBefore("b", "b") }, "a,b,c,d", true},
+		{"missing key", func(m *OrderedMap) bool { return m.MoveToFront("x") }, "a,b,c,d", false},
+		{"missing mark", func(m *OrderedMap) bool { return m.MoveAfter("a", "x") }, "a,b,c,d", false},
+	}
+
+	for _, c := range cases {
+		m := NewOrderedMap()
+		for _, k := range []string{"a", "b", "c", "d"} {
+			m.Set(k, k)
+		}
+		ok := c.move(m)
+		if keys := strings.Join(m.Keys(), ","); keys != c.keys || ok != c.ok {
+			t.Errorf("[title: %s] Expected keys: %s (%v), got: %s (%v)", c.title, c.keys, c.ok, keys, ok)
+		}
+	}
+}
+
+func TestOrderedMapBasics(t *testing.T) {
+	var m OrderedMap // Zero value is usable
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("b", 3)
+	if keys := strings.Join(m.Keys(), ","); keys != "b,a" {
+		t.Errorf("Expected keys: %s, got: %s", "b,a", keys)
+	}
+	if v, ok := m.Get("b"); v != 3 || !ok {
+		t.Errorf("Expected value: %v, got: %v", 3, v)
+	}
+	if !m.Delete("b") || m.Delete("b") || m.Len() != 1 {
+		t.Errorf("Expected b to be deleted once, keys: %v", m.Keys())
+	}
+
+	m.Set("c", 1)
+	m.Set("b", 1)
+	m.SortKeys(nil)
+	if keys := strings.Join(m.Keys(), ","); keys != "a,b,c" {
+		t.Errorf("Expected keys: %s, got: %s", "a,b,c", keys)
+	}
+	m.SortKeys(func(a, b string) bool { return a > b })
+	if keys := strings.Join(m.Keys(), ","); keys != "c,b,a" {
+		t.Errorf("Expected keys: %s, got: %s", "c,b,a", keys)
+	}
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	cases := []struct {
+		title string // Title of the test case
+		src   string // Input JSON
+		exp   string // Expected output JSON
+		isErr bool   // Tells if error is expected
+	}{
+		// Test success:
+		{"object", `{"z":1,"a":{"y":[{"c":true,"b":null}],"x":"s"}}`, `{"z":1,"a":{"y":[{"c":true,"b":null}],"x":"s"}}`, false},
+		{"duplicate key", `{"b":1,"a":2,"b":3}`, `{"b":3,"a":2}`, false},
+		{"empty", `{}`, `{}`, false},
+		{"scalar", `"s"`, `"s"`, false},
+		{"array", ` [1, {"b":1,"a":2}] `, `[1,{"b":1,"a":2}]`, false},
+
+		// Test errors:
+		{"syntax error", `{"a":}`, "", true},
+		{"trailing data error", `{} x`, "", true},
+		{"second value error", `{} {}`, "", true},
+	}
+
+	for _, c := range cases {
+		v, err := UnmarshalOrdered([]byte(c.src))
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+		if err != nil {
+			continue
+		}
+		data, err := json.Marshal(v)
+		if string(data) != c.exp || err != nil {
+			t.Errorf("[title: %s] Expected value: %v, got: %v, err value: %v", c.title, c.exp, string(data), err)
+		}
+	}
+
+	var m OrderedMap
+	if err := json.Unmarshal([]byte(`{"b":1,"a":2}`), &m); err != nil || strings.Join(m.Keys(), ",") != "b,a" {
+		t.Errorf("Expected keys: %s, got: %v, err value: %v", "b,a", m.Keys(), err)
+	}
+	if err := json.Unmarshal([]byte(`[]`), &m); err == nil {
+		t.Errorf("Expected error for non-object JSON")
+	}
+
+	dec := json.NewDecoder(strings.NewReader(`{"n":1.5}`))
+	dec.UseNumber()
+	v, err := DecodeOrdered(dec)
+	if n, _ := Get(v, "n"); n != json.Number("1.5") || err != nil {
+		t.Errorf("Expected value: %v, got: %v (%T), err value: %v", "1.5", n, n, err)
+	}
+}
+
+func TestOrderedMapOperations(t *testing.T) {
+	v, _ := UnmarshalOrdered([]byte(`{"b":{"y":1,"x":2},"a":[{"k":1}]}`))
+
+	if err := Set(v, 3, "b", "w"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := Delete(v, "y", "b"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if x, err := Get(v, "b", "x"); x != 2.0 || err != nil {
+		t.Errorf("Expected value: %v, got: %v, err value: %v", 2.0, x, err)
+	}
+	if _, err := Get(v, "b", 0); err == nil {
+		t.Errorf("Expected error for int path element")
+	}
+
+	// Walkers visit keys in order:
+	_, paths := GetAll(v, AnyDeep)
+	exp := [][]interface{}{{}, {"b"}, {"b", "x"}, {"b", "w"}, {"a"}, {"a", 0}, {"a", 0, "k"}}
+	if !reflect.DeepEqual(paths, exp) {
+		t.Errorf("Expected paths: %v, got: %v", exp, paths)
+	}
+
+	// ConvertMapI2MapS converts values in place:
+	Set(v, map[interface{}]interface{}{"k": 1}, "a", 0)
+	ConvertMapI2MapS(v)
+	if el, _ := Get(v, "a", 0); !reflect.DeepEqual(el, map[string]interface{}{"k": 1}) {
+		t.Errorf("Expected value: %v, got: %v", map[string]interface{}{"k": 1}, el)
+	}
+
+	if !Equal(v, map[string]interface{}{
+		"b": map[string]interface{}{"w": 3, "x": 2},
+		"a": []interface{}{map[interface{}]interface{}{"k": 1}},
+	}) {
+		t.Errorf("Expected ordered map to equal map")
+	}
+
+	// Omit works on a clone:
+	omitted := Omit(v, []interface{}{"b", "x"})
+	if data, _ := json.Marshal(omitted); string(data) != `{"b":{"w":3},"a":[{"k":1}]}` {
+		t.Errorf("Expected omitted: %s, got: %s", `{"b":{"w":3},"a":[{"k":1}]}`, data)
+	}
+	if data, _ := json.Marshal(v); string(data) != `{"b":{"x":2,"w":3},"a":[{"k":1}]}` {
+		t.Errorf("Expected original: %s, got: %s", `{"b":{"x":2,"w":3},"a":[{"k":1}]}`, data)
+	}
+}
+
+func TestOrderedMapNil(t *testing.T) {
+	v := map[string]interface{}{"a": (*OrderedMap)(nil)}
+	if _, err := Get(v, "a", "b"); err == nil {
+		t.Errorf("Expected error for missing key")
+	}
+	if _, paths := GetAll(v, AnyDeep); len(paths) != 2 {
+		t.Errorf("Expected paths: %d, got: %v", 2, paths)
+	}
+	if c := deepCopy(v); !Equal(c, v) {
+		t.Errorf("Expected copy: %v, got: %v", v, c)
+	}
+}
+
+// mustOrdered returns the JSON object decoded into an *OrderedMap,
+// it panics if src is invalid.
+func mustOrdered(src string) *OrderedMap {
+	v, err := UnmarshalOrdered([]byte(src))
+	if err != nil {
+		panic(err)
+	}
+	return v.(*OrderedMap)
+}
@@ -0,0 +1,371 @@
+package dyno
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Position is a location in a JSON source.
+type Position struct {
+	Offset int // Byte offset, 0-based
+	Line   int // Line number, 1-based
+	Column int // Column number (in bytes), 1-based
+}
+
+// String returns the line and column of the position.
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// PositionError is an error enriched with a source position.
+// It is returned for syntax errors by UnmarshalPositions and by
+// Positions.Annotate.
+type PositionError struct {
+	Pos  Position      // Source position
+	Path []interface{} // Concrete path the error relates to (may be nil for syntax errors)
+	Err  error         // The underlying error
+}
+
+// Error returns the error message.
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Pos, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
+
+// PositionOpts holds options for UnmarshalPositions.
+type PositionOpts struct {
+	// UseNumber tells if numbers are to be decoded into json.Number values
+	// instead of float64.
+	UseNumber bool
+
+	// Ordered tells if objects are to be decoded into *OrderedMap values
+	// instead of map[string]interface{}.
+	Ordered bool
+}
+
+// Positions is a side table of a JSON source mapping concrete paths of
+// values to their positions in the source.
+type Positions struct {
+	offsets    map[string]int // Offsets of values by path keys
+	lineStarts []int          // Offsets of line starts
+}
+
+// Get returns the position of the value denoted by the concrete path, and
+// whether it is known. The empty path denotes the root value.
+func (p *Positions) Get(path ...interface{}) (pos Position, ok bool) {
+	offset, ok := p.offsets[pathKey(path)]
+	if !ok {
+		return Position{}, false
+	}
+	return p.position(offset), true
+}
+
+// Locate returns the position of the value denoted by the concrete path,
+// or if that is unknown, the position of its deepest ancestor whose
+// position is known.
+func (p *Positions) Locate(path ...interface{}) Position {
+	for i := len(path); i > 0; i-- {
+		if pos, ok := p.Get(path[:i]...); ok {
+			return pos
+		}
+	}
+	pos, _ := p.Get()
+	return pos
+}
+
+// Annotate enriches err with the source position of the value denoted by
+// path, located by Locate. It is meant to be used with errors returned by
+// Get and the other functions of this package with the same path. If path
+// is empty and err is (or wraps) a *DecodeError, its path is used.
+//
+// The returned error is a *PositionError. If err is nil, nil is returned.
+func (p *Positions) Annotate(err error, path ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	var derr *DecodeError
+	if len(path) == 0 && errors.As(err, &derr) {
+		path = derr.Path
+	}
+	return &PositionError{Pos: p.Locate(path...), Path: path, Err: err}
+}
+
+// position returns the Position of the offset.
+func (p *Positions) position(offset int) Position {
+	// Index of the last line start <= offset:
+	line := sort.SearchInts(p.lineStarts, offset+1) - 1
+	return Position{
+		Offset: offset,
+		Line:   line + 1,
+		Column: offset - p.lineStarts[line] + 1,
+	}
+}
+
+// pathKey returns a string key of the concrete path.
+func pathKey(path []interface{}) string {
+	var b strings.Builder
+	for _, el := range path {
+		switch x := el.(type) {
+		case string:
+			b.WriteString(strconv.Quote(x))
+		case int:
+			b.WriteString(strconv.Itoa(x))
+		default:
+			fmt.Fprintf(&b, "%T(%v)", x, x)
+		}
+		b.WriteByte('/')
+	}
+	return b.String()
+}
+
+// UnmarshalPositions parses the JSON data into a dynamic object (like
+// json.Unmarshal would into an interface{} value), and also returns the
+// positions of all values in data by their concrete paths.
+//
+// Syntax errors are returned as *PositionError values.
+//
+// opts may be nil in which case the default options are used.
+func UnmarshalPositions(data []byte, opts *PositionOpts) (interface{}, *Positions, error) {
+	if opts == nil {
+		opts = &PositionOpts{}
+	}
+
+	pos := &Positions{offsets: map[string]int{}, lineStarts: []int{0}}
+	for i, c := range data {
+		if c == '\n' {
+			pos.lineStarts = append(pos.lineStarts, i+1)
+		}
+	}
+
+	s := &posScanner{data: data, opts: opts, pos: pos}
+	v, err := s.value(nil, 0)
+	if err == nil {
+		if s.skipSpace(); s.i < len(s.data) {
+			err = s.errorf("invalid character %q after top-level value", s.data[s.i])
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, pos, nil
+}
+
+// maxPosDepth is the max nesting depth of JSON values UnmarshalPositions
+// accepts.
+const maxPosDepth = 10000
+
+// posScanner is a JSON parser recording value positions.
+type posScanner struct {
+	data []byte
+	i    int // Current offset
+	opts *PositionOpts
+	pos  *Positions
+}
+
+// errorf returns a *PositionError at the current offset.
+func (s *posScanner) errorf(format string, a ...interface{}) error {
+	return &PositionError{Pos: s.pos.position(s.i), Err: fmt.Errorf(format, a...)}
+}
+
+// skipSpace skips JSON whitespace.
+func (s *posScanner) skipSpace() {
+	for ; s.i < len(s.data); s.i++ {
+		switch s.data[s.i] {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return
+		}
+	}
+}
+
+// value parses the next value whose concrete path is path.
+func (s *posScanner) value(path []interface{}, depth int) (interface{}, error) {
+	if depth > maxPosDepth {
+		return nil, s.errorf("exceeded max depth")
+	}
+
+	s.skipSpace()
+	if s.i >= len(s.data) {
+		return nil, s.errorf("unexpected end of JSON input")
+	}
+	s.pos.offsets[pathKey(path)] = s.i
+
+	switch c := s.data[s.i]; {
+	case c == '{':
+		return s.object(path, depth)
+	case c == '[':
+		return s.array(path, depth)
+	case c == '"':
+		return s.str()
+	case c == '-' || c >= '0' && c <= '9':
+		return s.number()
+	case c == 't':
+		return true, s.literal("true")
+	case c == 'f':
+		return false, s.literal("false")
+	case c == 'n':
+		return nil, s.literal("null")
+	default:
+		return nil, s.errorf("invalid character %q looking for beginning of value", c)
+	}
+}
+
+// object parses an object starting at the current offset.
+func (s *posScanner) object(path []interface{}, depth int) (interface{}, error) {
+	var ms map[string]interface{}
+	var om *OrderedMap
+	if s.opts.Ordered {
+		om = NewOrderedMap()
+	} else {
+		ms = map[string]interface{}{}
+	}
+	result := func() interface{} {
+		if om != nil {
+			return om
+		}
+		return ms
+	}
+
+	s.i++ // '{'
+	if s.skipSpace(); s.i < len(s.data) && s.data[s.i] == '}' {
+		s.i++
+		return result(), nil
+	}
+
+	for {
+		if s.skipSpace(); s.i >= len(s.data) || s.data[s.i] != '"' {
+			return nil, s.expected("object key")
+		}
+		k, err := s.str()
+		if err != nil {
+			return nil, err
+		}
+		key := k.(string)
+
+		if s.skipSpace(); s.i >= len(s.data) || s.data[s.i] != ':' {
+			return nil, s.expected("':' after object key")
+		}
+		s.i++
+
+		v, err := s.value(appendPath(path, key), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if om != nil {
+			om.Set(key, v)
+		} else {
+			ms[key] = v
+		}
+
+		if s.skipSpace(); s.i < len(s.data) && s.data[s.i] == ',' {
+			s.i++
+			continue
+		}
+		if s.i < len(s.data) && s.data[s.i] == '}' {
+			s.i++
+			return result(), nil
+		}
+		return nil, s.expected("',' or '}' after object value")
+	}
+}
+
+// array parses an array starting at the current offset.
+func (s *posScanner) array(path []interface{}, depth int) (interface{}, error) {
+	arr := []interface{}{}
+
+	s.i++ // '['
+	if s.skipSpace(); s.i < len(s.data) && s.data[s.i] == ']' {
+		s.i++
+		return arr, nil
+	}
+
+	for {
+		v, err := s.value(appendPath(path, len(arr)), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+
+		if s.skipSpace(); s.i < len(s.data) && s.data[s.i] == ',' {
+			s.i++
+			continue
+		}
+		if s.i < len(s.data) && s.data[s.i] == ']' {
+			s.i++
+			return arr, nil
+		}
+		return nil, s.expected("',' or ']' after array element")
+	}
+}
+
+// expected returns an error telling what was expected at the current offset.
+func (s *posScanner) expected(what string) error {
+	if s.i >= len(s.data) {
+		return s.errorf("unexpected end of JSON input")
+	}
+	return s.errorf("invalid character %q, expected %s", s.data[s.i], what)
+}
+
+// str parses a string starting at the current offset.
+func (s *posScanner) str() (interface{}, error) {
+	start := s.i
+	for s.i++; s.i < len(s.data); s.i++ {
+		switch s.data[s.i] {
+		case '\\':
+			s.i++ // Skip escaped char
+		case '"':
+			s.i++
+			var str string
+			if err := json.Unmarshal(s.data[start:s.i], &str); err != nil {
+				s.i = start
+				return nil, s.errorf("invalid string literal: %v", err)
+			}
+			return str, nil
+		}
+	}
+	return nil, s.errorf("unexpected end of JSON input")
+}
+
+// number parses a number starting at the current offset.
+func (s *posScanner) number() (interface{}, error) {
+	start := s.i
+	for ; s.i < len(s.data); s.i++ {
+		c := s.data[s.i]
+		if !(c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E') {
+			break
+		}
+	}
+
+	raw := s.data[start:s.i]
+	if !json.Valid(raw) {
+		s.i = start
+		return nil, s.errorf("invalid number literal: %s", raw)
+	}
+	if s.opts.UseNumber {
+		return json.Number(raw), nil
+	}
+	f, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		s.i = start
+		return nil, s.errorf("invalid number literal: %s", raw)
+	}
+	return f, nil
+}
+
+// literal parses the literal lit at the current offset.
+func (s *posScanner) literal(lit string) error {
+	if !bytes.HasPrefix(s.data[s.i:], []byte(lit)) {
+		return s.errorf("invalid literal, expected %s", lit)
+	}
+	s.i += len(lit)
+	return nil
+}
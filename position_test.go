@@ -0,0 +1,135 @@
+package dyno
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalPositions(t *testing.T) {
+	src := `{
+  "name": "app",
+  "ports": [80, 443],
+  "db": {"host": "h", "port": "xé"},
+  "on": true, "off": false, "none": null
+}`
+
+	v, pos, err := UnmarshalPositions([]byte(src), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var exp interface{}
+	json.Unmarshal([]byte(src), &exp)
+	if !reflect.DeepEqual(v, exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, v)
+	}
+
+	cases := []struct {
+		title string        // Title of the test case
+		path  []interface{} // Input path
+		line  int           // Expected line
+		col   int           // Expected column
+		ok    bool          // Expected ok
+	}{
+		{"root", []interface{}{}, 1, 1, true},
+		{"string", []interface{}{"name"}, 2, 11, true},
+		{"array", []interface{}{"ports"}, 3, 12, true},
+		{"array element", []interface{}{"ports", 1}, 3, 17, true},
+		{"nested", []interface{}{"db", "port"}, 4, 31, true},
+		{"literal", []interface{}{"none"}, 5, 37, true},
+		{"missing", []interface{}{"x"}, 0, 0, false},
+		{"index out of range", []interface{}{"ports", 2}, 0, 0, false},
+	}
+
+	for _, c := range cases {
+		p, ok := pos.Get(c.path...)
+		if p.Line != c.line || p.Column != c.col || ok != c.ok {
+			t.Errorf("[title: %s] Expected position: %d:%d (%v), got: %d:%d (%v)", c.title, c.line, c.col, c.ok, p.Line, p.Column, ok)
+		}
+	}
+}
+
+func TestUnmarshalPositionsOpts(t *testing.T) {
+	v, _, err := UnmarshalPositions([]byte(`{"b": 1.50, "a": [{}]}`), &PositionOpts{UseNumber: true, Ordered: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if keys := strings.Join(v.(*OrderedMap).Keys(), ","); keys != "b,a" {
+		t.Errorf("Expected keys: %s, got: %s", "b,a", keys)
+	}
+	if b, _ := Get(v, "b"); b != json.Number("1.50") {
+		t.Errorf("Expected value: %v, got: %v", "1.50", b)
+	}
+}
+
+func TestUnmarshalPositionsError(t *testing.T) {
+	cases := []struct {
+		title string // Title of the test case
+		src   string // Input JSON
+		line  int    // Expected line of the error
+		col   int    // Expected column of the error
+	}{
+		{"empty", ``, 1, 1},
+		{"missing value", "{\n  \"a\": }", 2, 8},
+		{"missing colon", `{"a" 1}`, 1, 6},
+		{"missing comma", `[1 2]`, 1, 4},
+		{"trailing comma", `[1,]`, 1, 4},
+		{"bad literal", `[tru]`, 1, 2},
+		{"bad number", `[1.]`, 1, 2},
+		{"bad string", "[\"a\tb\"]", 1, 2},
+		{"unterminated", `{"a": "b`, 1, 9},
+		{"trailing data", "{}\n x", 2, 2},
+		{"key not string", `{1: 2}`, 1, 2},
+	}
+
+	for _, c := range cases {
+		_, _, err := UnmarshalPositions([]byte(c.src), nil)
+		var perr *PositionError
+		if !errors.As(err, &perr) {
+			t.Errorf("[title: %s] Expected *PositionError, got: %v", c.title, err)
+			continue
+		}
+		if perr.Pos.Line != c.line || perr.Pos.Column != c.col {
+			t.Errorf("[title: %s] Expected position: %d:%d, got: %d:%d (%v)", c.title, c.line, c.col, perr.Pos.Line, perr.Pos.Column, err)
+		}
+	}
+}
+
+func TestPositionsAnnotate(t *testing.T) {
+	src := `{
+  "db": {"port": "x"}
+}`
+	v, pos, _ := UnmarshalPositions([]byte(src), nil)
+
+	path := []interface{}{"db", "port"}
+	_, err := GetInt(v, path...)
+	err = pos.Annotate(err, path...)
+	if exp := "line 2, column 18: expected int value, got: string"; err == nil || err.Error() != exp {
+		t.Errorf("Expected error: %s, got: %v", exp, err)
+	}
+
+	// Missing key is located at its parent:
+	path = []interface{}{"db", "user", "name"}
+	_, err = Get(v, path...)
+	if perr, ok := pos.Annotate(err, path...).(*PositionError); !ok || perr.Pos.Line != 2 || perr.Pos.Column != 9 {
+		t.Errorf("Expected position: 2:9, got: %v", perr)
+	}
+
+	// Decode errors carry their path:
+	var cfg struct {
+		DB struct {
+			Port int `json:"port"`
+		} `json:"db"`
+	}
+	err = pos.Annotate(Decode(v, &cfg))
+	if perr, ok := err.(*PositionError); !ok || perr.Pos.Column != 18 {
+		t.Errorf("Expected position: 2:18, got: %v", err)
+	}
+
+	if pos.Annotate(nil) != nil {
+		t.Errorf("Expected nil error")
+	}
+}
@@ -0,0 +1,114 @@
+package dyno
+
+// Project returns a new dynamic object containing only the values denoted
+// by the given paths, which may contain the wildcards Any and AnyDeep
+// (see GetAll). Selected values are deep copied, v is not modified.
+//
+// Maps on the way to the selected values are created with the same key
+// type as in v. Slices on the way keep their length, so elements keep their
+// positions; elements not selected are nil.
+//
+// Paths not present in v are ignored. If no path is present, an empty map
+// or slice of the same type as v is returned (nil if v is not a map or
+// slice). An empty path selects v as a whole.
+//
+// Containers on the way are projected into map[interface{}]interface{}
+// values (keyed by the container keys).
+func Project(v interface{}, paths ...[]interface{}) interface{} {
+	res := emptyLike(v)
+	for _, path := range paths {
+		_, concretes := GetAll(v, path...)
+		for _, p := range concretes {
+			res = projectPath(res, v, p)
+		}
+	}
+	return res
+}
+
+// Omit returns a deep copy of v without the values denoted by the given
+// paths, which may contain the wildcards Any and AnyDeep (see GetAll).
+// v is not modified.
+//
+// Selected map keys are removed. Selected slice elements are set to nil,
+// so other elements keep their positions. Selected Container elements are
+// deleted.
+//
+// Paths not present in v are ignored. An empty path selects v as a whole,
+// in which case nil is returned.
+func Omit(v interface{}, paths ...[]interface{}) interface{} {
+	res := deepCopy(v)
+	for _, path := range paths {
+		if len(path) == 0 {
+			return nil
+		}
+		_, concretes := GetAll(res, path...)
+		for _, p := range concretes {
+			omitPath(res, p)
+		}
+	}
+	return res
+}
+
+// emptyLike returns an empty map or slice of the same type as v.
+// Slices keep their length (all elements being nil).
+// Returns nil if v is not a map or slice.
+func emptyLike(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return map[string]interface{}{}
+	case map[interface{}]interface{}:
+		return map[interface{}]interface{}{}
+	case []interface{}:
+		return make([]interface{}, len(x))
+	case *OrderedMap:
+		return NewOrderedMap()
+	case Container:
+		return map[interface{}]interface{}{}
+	}
+	return nil
+}
+
+// projectPath copies the value denoted by the concrete path from src into
+// dst, creating missing maps and slices in dst like their counterparts in
+// src. The new dst is returned.
+func projectPath(dst, src interface{}, path []interface{}) interface{} {
+	if len(path) == 0 {
+		return deepCopy(src)
+	}
+
+	child, exists, err := getElem(src, path[0], 0)
+	if err != nil || !exists {
+		return dst
+	}
+	if dst == nil {
+		dst = emptyLike(src)
+	}
+	cur, _, _ := getElem(dst, path[0], 0)
+
+	setElem(dst, path[0], 0, projectPath(cur, child, path[1:]))
+	return dst
+}
+
+// omitPath removes the value denoted by the concrete path from v.
+// Paths no longer present (e.g. because an ancestor was removed) are
+// ignored.
+func omitPath(v interface{}, path []interface{}) {
+	node, err := getParent(v, path)
+	if err != nil {
+		return
+	}
+
+	key := path[len(path)-1]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			delete(n, k)
+		}
+	case map[interface{}]interface{}:
+		delete(n, key)
+	case []interface{}:
+		setElem(n, key, len(path)-1, nil)
+	case Container:
+		n.DeleteElem(key)
+	}
+}
@@ -0,0 +1,132 @@
+package dyno
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// projectDoc is the input document of the projection tests in JSON.
+const projectDoc = `{
+	"id": 1,
+	"name": "x",
+	"secret": "s",
+	"owner": {"name": "o", "email": "e"},
+	"items": [{"sku": "a", "cost": 1}, {"sku": "b", "cost": 2}, 3]
+}`
+
+func TestProject(t *testing.T) {
+	cases := []struct {
+		title string          // Title of the test case
+		paths [][]interface{} // Paths to keep
+		exp   string          // Expected result in JSON
+	}{
+		{
+			title: "top-level keys",
+			paths: [][]interface{}{{"id"}, {"name"}},
+			exp:   `{"id": 1, "name": "x"}`,
+		},
+		{
+			title: "nested key",
+			paths: [][]interface{}{{"owner", "email"}},
+			exp:   `{"owner": {"email": "e"}}`,
+		},
+		{
+			title: "wildcard into slice",
+			paths: [][]interface{}{{"items", Any, "sku"}},
+			exp:   `{"items": [{"sku": "a"}, {"sku": "b"}, null]}`,
+		},
+		{
+			title: "slice index keeps position",
+			paths: [][]interface{}{{"items", 1}},
+			exp:   `{"items": [null, {"sku": "b", "cost": 2}, null]}`,
+		},
+		{
+			title: "overlapping paths",
+			paths: [][]interface{}{{"owner", "name"}, {"owner"}},
+			exp:   `{"owner": {"name": "o", "email": "e"}}`,
+		},
+		{
+			title: "missing paths",
+			paths: [][]interface{}{{"x"}, {"owner", "x"}},
+			exp:   `{}`,
+		},
+		{
+			title: "empty path",
+			paths: [][]interface{}{{}},
+			exp:   projectDoc,
+		},
+	}
+
+	for _, c := range cases {
+		v := decodeJSON(projectDoc)
+		res := Project(v, c.paths...)
+		if !Equal(res, decodeJSON(c.exp)) {
+			got, _ := json.Marshal(res)
+			t.Errorf("[title: %s] Expected value: %s, got: %s", c.title, c.exp, got)
+		}
+		if !Equal(v, decodeJSON(projectDoc)) {
+			t.Errorf("[title: %s] Input must not be modified", c.title)
+		}
+	}
+
+	// Map kinds are preserved:
+	v := map[interface{}]interface{}{"a": map[interface{}]interface{}{"b": 1, "c": 2}}
+	res := Project(v, []interface{}{"a", "b"})
+	if m, ok := res.(map[interface{}]interface{}); !ok {
+		t.Errorf("Expected map[interface{}]interface{}, got: %T", res)
+	} else if _, ok := m["a"].(map[interface{}]interface{}); !ok {
+		t.Errorf("Expected map[interface{}]interface{}, got: %T", m["a"])
+	}
+}
+
+func TestOmit(t *testing.T) {
+	cases := []struct {
+		title string          // Title of the test case
+		paths [][]interface{} // Paths to remove
+		exp   string          // Expected result in JSON
+	}{
+		{
+			title: "top-level and nested keys",
+			paths: [][]interface{}{{"secret"}, {"owner", "email"}},
+			exp: `{"id": 1, "name": "x", "owner": {"name": "o"},
+				"items": [{"sku": "a", "cost": 1}, {"sku": "b", "cost": 2}, 3]}`,
+		},
+		{
+			title: "wildcard into slice",
+			paths: [][]interface{}{{"items", Any, "cost"}, {"owner"}, {"secret"}},
+			exp:   `{"id": 1, "name": "x", "items": [{"sku": "a"}, {"sku": "b"}, 3]}`,
+		},
+		{
+			title: "slice element keeps positions",
+			paths: [][]interface{}{{"items", 0}, {"owner"}, {"secret"}},
+			exp:   `{"id": 1, "name": "x", "items": [null, {"sku": "b", "cost": 2}, 3]}`,
+		},
+		{
+			title: "deep wildcard",
+			paths: [][]interface{}{{AnyDeep, "name"}, {"items"}, {"secret"}},
+			exp:   `{"id": 1, "owner": {"email": "e"}}`,
+		},
+		{
+			title: "missing paths",
+			paths: [][]interface{}{{"x"}, {"owner", "x", "y"}, {"owner"}, {"items"}},
+			exp:   `{"id": 1, "name": "x", "secret": "s"}`,
+		},
+		{
+			title: "empty path",
+			paths: [][]interface{}{{}},
+			exp:   `null`,
+		},
+	}
+
+	for _, c := range cases {
+		v := decodeJSON(projectDoc)
+		res := Omit(v, c.paths...)
+		if !Equal(res, decodeJSON(c.exp)) {
+			got, _ := json.Marshal(res)
+			t.Errorf("[title: %s] Expected value: %s, got: %s", c.title, c.exp, got)
+		}
+		if !Equal(v, decodeJSON(projectDoc)) {
+			t.Errorf("[title: %s] Input must not be modified", c.title)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package dyno
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ReflectFallback enables the reflection fallback: when set to true, nodes
+// that are not of the types map[string]interface{},
+// map[interface{}]interface{} or []interface{} are handled using
+// reflection if they are maps, slices, arrays or pointers to these.
+// This allows navigating and modifying e.g. map[string]string or
+// []map[string]interface{} values.
+//
+// Go structs (and pointers to structs) embedded in dynamic objects are
+// always handled using reflection, regardless of ReflectFallback, so the
+// same path works whether a subtree is dynamic or typed. Struct fields are
+// denoted by string path elements: by their json tag names, yaml tag names
+// or Go names (in this order of precedence). Fields tagged with "-" and
+// unexported fields are not accessible. Fields of embedded structs are
+// promoted (like in encoding/json). Struct fields can only be set if the
+// struct is addressable (e.g. it is reached through a pointer). Typed maps,
+// slices and arrays in struct fields require ReflectFallback to be
+// navigated.
+//
+// Path elements are converted to map key types if they are assignable or
+// of the same kind (e.g. a string path element can be used with a map
+// having a key type whose underlying type is string). Values being set are
+// converted likewise; nil can be set if the element type is nillable.
+// Array elements can only be set if the array is addressable (e.g. it is
+// reached through a pointer).
+//
+// The fast type switch on the natively supported types is always tried
+// first, so enabling this has no performance penalty for those types.
+//
+// ReflectFallback is disabled by default. It should be set once at program
+// startup, it must not be changed concurrently with other calls of this
+// package.
+var ReflectFallback = false
+
+// reflectNode returns node as a reflect.Value if node is a struct, or if
+// ReflectFallback is enabled and node is a map, slice or array, or if node
+// is a non-nil pointer to these.
+func reflectNode(node interface{}) (reflect.Value, bool) {
+	if node == nil {
+		return reflect.Value{}, false
+	}
+
+	rv := reflect.ValueOf(node)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		return rv, true
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rv, ReflectFallback
+	}
+	return reflect.Value{}, false
+}
+
+// reflectKey converts the path element el whose index in the path is i to
+// a key of the map rv.
+func reflectKey(rv reflect.Value, el interface{}, i int) (reflect.Value, error) {
+	kt := rv.Type().Key()
+	if el == nil {
+		if isNillable(kt.Kind()) {
+			return reflect.Zero(kt), nil
+		}
+		return reflect.Value{}, fmt.Errorf("expected %v path element, got: <nil> (path element idx: %d)", kt, i)
+	}
+
+	k, ok := convertTo(reflect.ValueOf(el), kt)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("expected %v path element, got: %T (path element idx: %d)", kt, el, i)
+	}
+	return k, nil
+}
+
+// reflectIndex returns the path element el whose index in the path is i as
+// an index of the slice or array rv.
+func reflectIndex(rv reflect.Value, el interface{}, i int) (int, error) {
+	idx, ok := el.(int)
+	if !ok {
+		return 0, fmt.Errorf("expected int path element, got: %T (path element idx: %d)", el, i)
+	}
+	if idx < 0 || idx >= rv.Len() {
+		return 0, fmt.Errorf("index out of range: %d (path element idx: %d)", idx, i)
+	}
+	return idx, nil
+}
+
+// reflectValue converts value to a value assignable to type t.
+// i is the index of the path element denoting the element to assign to.
+func reflectValue(value interface{}, t reflect.Type, i int) (reflect.Value, error) {
+	if value == nil {
+		if isNillable(t.Kind()) {
+			return reflect.Zero(t), nil
+		}
+	} else if vv, ok := convertTo(reflect.ValueOf(value), t); ok {
+		return vv, nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot assign %T to element of type %v (path element idx: %d)", value, t, i)
+}
+
+// convertTo converts v to type t if v is assignable to t, or v is
+// convertible to t and is of the same kind.
+func convertTo(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	switch {
+	case v.Type().AssignableTo(t):
+		return v, true
+	case v.Kind() == t.Kind() && v.Type().ConvertibleTo(t):
+		return v.Convert(t), true
+	}
+	return reflect.Value{}, false
+}
+
+// isNillable tells if values of kind k may be nil.
+func isNillable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return true
+	}
+	return false
+}
+
+// reflectGetElem is the reflection fallback of getElem.
+func reflectGetElem(rv reflect.Value, el interface{}, i int) (value interface{}, exists bool, err error) {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return structGetElem(rv, el, i)
+	case reflect.Map:
+		k, err := reflectKey(rv, el, i)
+		if err != nil {
+			return nil, false, err
+		}
+		v := rv.MapIndex(k)
+		if !v.IsValid() {
+			return nil, false, nil
+		}
+		return v.Interface(), true, nil
+	}
+
+	idx, err := reflectIndex(rv, el, i)
+	if err != nil {
+		return nil, false, err
+	}
+	return rv.Index(idx).Interface(), true, nil
+}
+
+// reflectSetElem is the reflection fallback of setElem.
+func reflectSetElem(rv reflect.Value, el interface{}, i int, value interface{}) error {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return structSetElem(rv, el, i, value)
+	case reflect.Map:
+		if rv.IsNil() {
+			return fmt.Errorf("cannot set element of nil map %v (path element idx: %d)", rv.Type(), i)
+		}
+		k, err := reflectKey(rv, el, i)
+		if err != nil {
+			return err
+		}
+		v, err := reflectValue(value, rv.Type().Elem(), i)
+		if err != nil {
+			return err
+		}
+		rv.SetMapIndex(k, v)
+		return nil
+	}
+
+	idx, err := reflectIndex(rv, el, i)
+	if err != nil {
+		return err
+	}
+	elem := rv.Index(idx)
+	if !elem.CanSet() {
+		return fmt.Errorf("cannot set element of unaddressable %v (path element idx: %d)", rv.Type(), i)
+	}
+	v, err := reflectValue(value, elem.Type(), i)
+	if err != nil {
+		return err
+	}
+	elem.Set(v)
+	return nil
+}
+
+// reflectDelete is the reflection fallback of Delete.
+func reflectDelete(v interface{}, rv reflect.Value, key interface{}, path []interface{}) error {
+	switch rv.Kind() {
+	case reflect.Map:
+		k, err := reflectKey(rv, key, len(path))
+		if err != nil {
+			return err
+		}
+		if !rv.IsNil() {
+			rv.SetMapIndex(k, reflect.Value{})
+		}
+		return nil
+
+	case reflect.Slice:
+		idx, err := reflectIndex(rv, key, len(path))
+		if err != nil {
+			return err
+		}
+		if !rv.CanSet() && len(path) == 0 {
+			return fmt.Errorf("cannot replace unaddressable %v (pass a pointer)", rv.Type())
+		}
+		n := rv.Len()
+		reflect.Copy(rv.Slice(idx, n), rv.Slice(idx+1, n))
+		// Clear the emptied element:
+		last := rv.Index(n - 1)
+		last.Set(reflect.Zero(last.Type()))
+		return reflectStoreSlice(v, rv, rv.Slice(0, n-1), path)
+	}
+
+	return fmt.Errorf("cannot delete element of %v", rv.Type())
+}
+
+// reflectAppend is the reflection fallback of Append and AppendMore.
+func reflectAppend(v interface{}, rv reflect.Value, values []interface{}, path []interface{}) error {
+	if rv.Kind() != reflect.Slice {
+		return fmt.Errorf("expected slice node, got: %v (path element idx: %d)", rv.Type(), len(path)-1)
+	}
+
+	s := rv
+	for _, value := range values {
+		vv, err := reflectValue(value, rv.Type().Elem(), len(path))
+		if err != nil {
+			return err
+		}
+		s = reflect.Append(s, vv)
+	}
+	return reflectStoreSlice(v, rv, s, path)
+}
+
+// reflectStoreSlice stores the new slice s in place of the slice rv
+// denoted by path: directly if rv is addressable (it was reached through a
+// pointer), else by setting it in its parent.
+func reflectStoreSlice(v interface{}, rv, s reflect.Value, path []interface{}) error {
+	if rv.CanSet() {
+		rv.Set(s)
+		return nil
+	}
+	if len(path) == 0 {
+		return fmt.Errorf("cannot replace unaddressable %v (pass a pointer)", rv.Type())
+	}
+	return Set(v, s.Interface(), path...)
+}
+
+// reflectChildren calls f for each element of the map (in sorted key order),
+// slice, array or struct (in field declaration order) rv.
+func reflectChildren(rv reflect.Value, f func(key, child interface{}) error) error {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return structChildren(rv, f)
+	case reflect.Map:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessKey(keys[i].Interface(), keys[j].Interface())
+		})
+		for _, k := range keys {
+			if err := f(k.Interface(), rv.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		if err := f(i, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
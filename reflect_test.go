@@ -0,0 +1,220 @@
+package dyno
+
+import (
+	"reflect"
+	"testing"
+)
+
+// enableReflect enables ReflectFallback, the returned function restores it.
+func enableReflect() func() {
+	old := ReflectFallback
+	ReflectFallback = true
+	return func() { ReflectFallback = old }
+}
+
+type testKey string
+
+func TestReflectGet(t *testing.T) {
+	defer enableReflect()()
+
+	arr := [3]int{1, 2, 3}
+	v := map[string]interface{}{
+		"ms":   map[string]string{"a": "x"},
+		"sm":   []map[string]interface{}{{"b": 1}},
+		"arr":  &arr,
+		"keys": map[testKey]int{"k": 2},
+		"ints": map[int]string{3: "three"},
+	}
+
+	cases := []struct {
+		title string        // Title of the test case
+		path  []interface{} // Input path
+		value interface{}   // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{"map[string]string", []interface{}{"ms", "a"}, "x", false},
+		{"slice of maps", []interface{}{"sm", 0, "b"}, 1, false},
+		{"pointer to array", []interface{}{"arr", 2}, 3, false},
+		{"named key type", []interface{}{"keys", "k"}, 2, false},
+		{"int key", []interface{}{"ints", 3}, "three", false},
+
+		// Test errors:
+		{"missing key error", []interface{}{"ms", "x"}, nil, true},
+		{"invalid key type error", []interface{}{"ms", 1}, nil, true},
+		{"string to int key error", []interface{}{"ints", "3"}, nil, true},
+		{"index out of range error", []interface{}{"arr", 3}, nil, true},
+		{"non-container error", []interface{}{"ms", "a", "b"}, nil, true},
+	}
+
+	for _, c := range cases {
+		value, err := Get(v, c.path...)
+		if value != c.value {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	ReflectFallback = false
+	if _, err := Get(v, "ms", "a"); err == nil {
+		t.Errorf("Expected error when reflection fallback is disabled")
+	}
+}
+
+func TestReflectSet(t *testing.T) {
+	defer enableReflect()()
+
+	arr := [2]string{}
+	v := map[string]interface{}{
+		"mi":   map[string]int{},
+		"mp":   map[string]*int{"p": new(int)},
+		"sm":   []map[string]interface{}{{}},
+		"arr":  &arr,
+		"varr": [2]string{},
+		"keys": map[testKey]testKey{},
+	}
+
+	cases := []struct {
+		title string        // Title of the test case
+		path  []interface{} // Input path
+		value interface{}   // Value to set
+		exp   interface{}   // Expected value after set
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{"map[string]int", []interface{}{"mi", "a"}, 1, 1, false},
+		{"nil pointer", []interface{}{"mp", "p"}, nil, (*int)(nil), false},
+		{"slice of maps", []interface{}{"sm", 0, "b"}, "x", "x", false},
+		{"pointer to array", []interface{}{"arr", 1}, "y", "y", false},
+		{"convertible key and value", []interface{}{"keys", "k"}, "v", testKey("v"), false},
+
+		// Test errors:
+		{"non-assignable value error", []interface{}{"mi", "a"}, "x", 1, true},
+		{"non-nillable nil error", []interface{}{"mi", "a"}, nil, 1, true},
+		{"float to int error", []interface{}{"mi", "a"}, 1.5, 1, true},
+		{"unaddressable array error", []interface{}{"varr", 0}, "z", "", true},
+		{"index out of range error", []interface{}{"sm", 1, "b"}, "x", nil, true},
+	}
+
+	for _, c := range cases {
+		err := Set(v, c.value, c.path...)
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+		value, _ := Get(v, c.path...)
+		if !reflect.DeepEqual(value, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, value)
+		}
+	}
+
+	if arr[1] != "y" {
+		t.Errorf("Expected array element: %q, got: %q", "y", arr[1])
+	}
+}
+
+func TestReflectDeleteAppend(t *testing.T) {
+	defer enableReflect()()
+
+	ss := []string{"a", "b", "c"}
+	v := map[string]interface{}{
+		"ms": map[string]string{"a": "x", "b": "y"},
+		"ss": []string{"a", "b", "c"},
+		"ps": &ss,
+	}
+
+	if err := Delete(v, "a", "ms"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := Delete(v, 1, "ss"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := Delete(v, 0, "ps"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := Append(v, "d", "ss"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := AppendMore(v, []interface{}{"e", "f"}, "ps"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	exp := map[string]interface{}{
+		"ms": map[string]string{"b": "y"},
+		"ss": []string{"a", "c", "d"},
+		"ps": &ss,
+	}
+	if !reflect.DeepEqual(v, exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, v)
+	}
+	if expSS := []string{"b", "c", "e", "f"}; !reflect.DeepEqual(ss, expSS) {
+		t.Errorf("Expected value: %v, got: %v", expSS, ss)
+	}
+
+	if err := Append(v, 1, "ss"); err == nil {
+		t.Errorf("Expected error for non-assignable value")
+	}
+	if err := Append(v, "x", "ms"); err == nil {
+		t.Errorf("Expected error for non-slice node")
+	}
+	if err := Delete(v, 5, "ss"); err == nil {
+		t.Errorf("Expected error for index out of range")
+	}
+	if err := Delete([]string{"a"}, 0); err == nil {
+		t.Errorf("Expected error for unaddressable root slice")
+	}
+}
+
+func TestReflectWildcard(t *testing.T) {
+	defer enableReflect()()
+
+	v := map[string]interface{}{
+		"sm": []map[string]interface{}{{"n": 1}, {"n": 2}},
+		"ms": map[string]string{"b": "y", "a": "x"},
+	}
+
+	values, _ := GetAll(v, "sm", Any, "n")
+	if exp := []interface{}{1, 2}; !reflect.DeepEqual(values, exp) {
+		t.Errorf("Expected values: %v, got: %v", exp, values)
+	}
+	values, _ = GetAll(v, "ms", Any)
+	if exp := []interface{}{"x", "y"}; !reflect.DeepEqual(values, exp) {
+		t.Errorf("Expected values: %v, got: %v", exp, values)
+	}
+
+	if n, _, err := DeleteAll(v, "ms", Any); n != 2 || err != nil {
+		t.Errorf("Expected 2 deleted elements, got: %d, err value: %v", n, err)
+	}
+	if m := v["ms"].(map[string]string); len(m) != 0 {
+		t.Errorf("Expected empty map, got: %v", m)
+	}
+
+	// Named key type:
+	v["mk"] = map[testKey]string{"a": "x", "b": "y"}
+	if n, _, err := DeleteAll(v, "mk", "a"); n != 1 || err != nil {
+		t.Errorf("Expected 1 deleted element, got: %d, err value: %v", n, err)
+	}
+	if m, exp := v["mk"], map[testKey]string{"b": "y"}; !reflect.DeepEqual(m, exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, m)
+	}
+
+	// Typed slices (stored in the parent, or updated in place through a pointer):
+	v["ss"] = []string{"a", "b", "c"}
+	if n, _, err := DeleteAll(v, "ss", Any); n != 3 || err != nil {
+		t.Errorf("Expected 3 deleted elements, got: %d, err value: %v", n, err)
+	}
+	if s, exp := v["ss"], []string{}; !reflect.DeepEqual(s, exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, s)
+	}
+	ps := &[]string{"a", "b", "c"}
+	if n, _, err := DeleteAll(ps, 1); n != 1 || err != nil {
+		t.Errorf("Expected 1 deleted element, got: %d, err value: %v", n, err)
+	}
+	if exp := []string{"a", "c"}; !reflect.DeepEqual(*ps, exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, *ps)
+	}
+	if _, _, err := DeleteAll([]string{"a"}, 0); err == nil {
+		t.Errorf("Expected error for root slice")
+	}
+}
@@ -0,0 +1,552 @@
+package dyno
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// RewriteKind is the kind of a RewriteOp.
+type RewriteKind int
+
+const (
+	// RewriteSet sets the value denoted by the path to RewriteOp.Value.
+	RewriteSet RewriteKind = iota
+
+	// RewriteDelete deletes the value denoted by the path.
+	RewriteDelete
+
+	// RewriteTransform replaces the value denoted by the path with the
+	// value returned by RewriteOp.Transform.
+	RewriteTransform
+)
+
+// String returns the name of the rewrite kind.
+func (k RewriteKind) String() string {
+	switch k {
+	case RewriteSet:
+		return "set"
+	case RewriteDelete:
+		return "delete"
+	case RewriteTransform:
+		return "transform"
+	}
+	return fmt.Sprintf("RewriteKind(%d)", int(k))
+}
+
+// RewriteOp is an operation of Rewrite.
+type RewriteOp struct {
+	// Kind of the operation.
+	Kind RewriteKind
+
+	// Path of the values to operate on. It may contain the wildcards Any
+	// and AnyDeep (see GetAll), but its last element cannot be AnyDeep.
+	// The empty path denotes the root value (which cannot be deleted).
+	Path []interface{}
+
+	// Value to set, used by RewriteSet. It is deep copied for each match.
+	Value interface{}
+
+	// Transform returns the new value of the value at path, used by
+	// RewriteTransform. It is only called for existing values.
+	Transform func(path []interface{}, value interface{}) (interface{}, error)
+}
+
+// Rewrite reads JSON from r, applies ops to it and writes the result to w,
+// without loading the whole JSON document: only the values denoted by the
+// paths of ops are buffered (and not even those that are set or deleted),
+// and arrays whose elements are deleted by an op if a later op uses a
+// concrete index in them.
+// r may hold multiple JSON values (e.g. newline-delimited JSON), each is
+// rewritten and written on its own line. The output is compact JSON, numbers
+// are written as-is.
+//
+// Operations are applied in the given order, as if they were applied to the
+// whole document one after the other (like SetAll, DeleteAll and UpdateAll
+// would). Values are passed to RewriteOp.Transform as dynamic objects
+// decoded like json.Decoder.Decode would decode them into an interface{}
+// value after json.Decoder.UseNumber() (so numbers are json.Number values).
+//
+// Like SetAll, if the last path element of a RewriteSet op is a map key, it
+// is set in all matching maps, even if it does not exist yet (missing slice
+// elements are not created). To change existing values only, use
+// RewriteTransform.
+func Rewrite(r io.Reader, w io.Writer, ops ...RewriteOp) error {
+	for i, op := range ops {
+		if err := op.check(); err != nil {
+			return fmt.Errorf("invalid op at index %d: %v", i, err)
+		}
+	}
+
+	rw := &rewriter{dec: json.NewDecoder(r), w: bufio.NewWriter(w), ops: ops}
+	rw.dec.UseNumber()
+	rw.enc = json.NewEncoder(&rw.buf)
+	rw.enc.SetEscapeHTML(false)
+
+	for rw.dec.More() {
+		var err error
+		if rw.matches(nil) {
+			var v interface{}
+			if v, _, err = rw.edit(nil); err == nil { // The root cannot be deleted
+				err = rw.write(v)
+			}
+		} else {
+			err = rw.stream(nil, true)
+		}
+		if err != nil {
+			return err
+		}
+		rw.w.WriteByte('\n')
+	}
+	if _, err := rw.dec.Token(); err != io.EOF {
+		if err == nil {
+			err = fmt.Errorf("unexpected delimiter")
+		}
+		return err
+	}
+
+	return rw.w.Flush()
+}
+
+// check checks if op is valid.
+func (op *RewriteOp) check() error {
+	switch op.Kind {
+	case RewriteSet:
+	case RewriteDelete:
+		if len(op.Path) == 0 {
+			return fmt.Errorf("path cannot be empty")
+		}
+	case RewriteTransform:
+		if op.Transform == nil {
+			return fmt.Errorf("missing transform function")
+		}
+	default:
+		return fmt.Errorf("invalid kind: %v", op.Kind)
+	}
+	if len(op.Path) > 0 && op.Path[len(op.Path)-1] == AnyDeep {
+		return fmt.Errorf("last path element cannot be AnyDeep")
+	}
+	return nil
+}
+
+// apply applies op to the value at path. exists tells if the value exists.
+func (op *RewriteOp) apply(path []interface{}, value interface{}, exists bool) (interface{}, bool, error) {
+	switch op.Kind {
+	case RewriteSet:
+		return deepCopy(op.Value), true, nil
+	case RewriteDelete:
+		return nil, false, nil
+	}
+
+	if !exists {
+		return nil, false, nil
+	}
+	value, err := op.Transform(path, value)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to transform %v: %v", path, err)
+	}
+	return value, true, nil
+}
+
+// rewriter holds the state of a Rewrite call.
+type rewriter struct {
+	dec *json.Decoder
+	w   *bufio.Writer
+	ops []RewriteOp
+
+	buf bytes.Buffer  // Buffer of enc
+	enc *json.Encoder // Encoder to encode values with
+}
+
+// matches tells if an op matches path.
+func (rw *rewriter) matches(path []interface{}) bool {
+	for i := range rw.ops {
+		if matchPath(rw.ops[i].Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// below tells if an op may match a descendant of path.
+func (rw *rewriter) below(path []interface{}) bool {
+	for i := range rw.ops {
+		if matchBelow(rw.ops[i].Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// shifts tells if an op deletes elements of the array at path and a later
+// op (which may match its descendants) has a concrete index in its path,
+// as indices of later ops refer to the elements remaining after deletions.
+func (rw *rewriter) shifts(path []interface{}) bool {
+	for i := range rw.ops {
+		op := &rw.ops[i]
+		if op.Kind != RewriteDelete || !matchPath(op.Path[:len(op.Path)-1], path) {
+			continue
+		}
+		for _, later := range rw.ops[i+1:] {
+			if !matchBelow(later.Path, path) {
+				continue
+			}
+			for _, el := range later.Path {
+				if _, ok := el.(int); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// edit reads the next value whose path is matched by an op, and returns it
+// after applying ops. exists tells if the value still exists.
+//
+// The value is not decoded (just skipped) if an op sets or deletes it, as
+// that overwrites the effects of preceding ops.
+func (rw *rewriter) edit(path []interface{}) (v interface{}, exists bool, err error) {
+	start := 0
+	for i := len(rw.ops) - 1; i >= 0; i-- {
+		if rw.ops[i].Kind != RewriteTransform && matchPath(rw.ops[i].Path, path) {
+			start = i
+			break
+		}
+	}
+
+	if start > 0 || rw.ops[0].Kind != RewriteTransform && matchPath(rw.ops[0].Path, path) {
+		err = rw.skip()
+	} else {
+		err = rw.dec.Decode(&v)
+		exists = true
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return rw.apply(path, v, exists, rw.ops[start:])
+}
+
+// apply applies ops to the value at path. exists tells if the value exists.
+func (rw *rewriter) apply(path []interface{}, v interface{}, exists bool, ops []RewriteOp) (interface{}, bool, error) {
+	var err error
+	for i := range ops {
+		op := &ops[i]
+		if exists && matchBelow(op.Path, path) {
+			if v, err = applyBelow(v, path, op); err != nil {
+				return nil, false, err
+			}
+		}
+		if matchPath(op.Path, path) {
+			if v, exists, err = op.apply(path, v, exists); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return v, exists, nil
+}
+
+// applyBelow applies op to the descendants of node (which is at path),
+// deepest values first. The new node is returned (slices shrink if
+// elements are deleted).
+func applyBelow(node interface{}, path []interface{}, op *RewriteOp) (interface{}, error) {
+	var keys []interface{}
+	walkChildren(node, path, walkedPtrs{}, func(key, child interface{}, p []interface{}) (interface{}, error) {
+		keys = append(keys, key)
+		return child, nil
+	})
+
+	var dels []interface{}
+	for _, key := range keys {
+		p := appendPath(path, key)
+		child, _, _ := getElem(node, key, len(path))
+
+		var err error
+		if matchBelow(op.Path, p) {
+			if child, err = applyBelow(child, p, op); err != nil {
+				return nil, err
+			}
+		}
+		exists := true
+		if matchPath(op.Path, p) {
+			if child, exists, err = op.apply(p, child, true); err != nil {
+				return nil, err
+			}
+		}
+		if !exists {
+			dels = append(dels, key)
+			continue
+		}
+		if err := setElem(node, key, len(path), child); err != nil {
+			return nil, err
+		}
+	}
+
+	if key, ok := createKey(op, path); ok {
+		if _, exists, err := getElem(node, key, len(path)); err == nil && !exists {
+			if err := setElem(node, key, len(path), deepCopy(op.Value)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i := len(dels) - 1; i >= 0; i-- {
+		switch n := node.(type) {
+		case []interface{}:
+			idx := dels[i].(int)
+			node = append(n[:idx], n[idx+1:]...)
+		default:
+			if err := Delete(node, dels[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return node, nil
+}
+
+// stream copies the next value at path to the output, applying ops to its
+// descendants. active tells if ops may match descendants (if false, the
+// value is copied as-is).
+func (rw *rewriter) stream(path []interface{}, active bool) error {
+	active = active && rw.below(path)
+
+	tok, err := rw.dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return rw.write(tok)
+	}
+
+	switch delim {
+	case '{':
+		return rw.object(path, active)
+	case '[':
+		return rw.array(path, active)
+	}
+	return fmt.Errorf("unexpected delimiter: %v", delim)
+}
+
+// object copies the object (whose opening delimiter is already read) at
+// path to the output, applying ops to its elements if active.
+func (rw *rewriter) object(path []interface{}, active bool) error {
+	rw.w.WriteByte('{')
+	first := true
+	writeKey := func(key string) error {
+		if !first {
+			rw.w.WriteByte(',')
+		}
+		first = false
+		if err := rw.write(key); err != nil {
+			return err
+		}
+		return rw.w.WriteByte(':')
+	}
+
+	seen := map[string]bool{}
+	for rw.dec.More() {
+		tok, err := rw.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		p := appendPath(path, key)
+		seen[key] = true
+
+		if active && rw.matches(p) {
+			v, exists, err := rw.edit(p)
+			if err != nil {
+				return err
+			}
+			if exists {
+				if err := writeKey(key); err != nil {
+					return err
+				}
+				if err := rw.write(v); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := writeKey(key); err != nil {
+			return err
+		}
+		if err := rw.stream(p, active); err != nil {
+			return err
+		}
+	}
+	if _, err := rw.dec.Token(); err != nil { // Closing '}'
+		return err
+	}
+
+	// Create missing keys set by ops:
+	for i := range rw.ops {
+		if !active {
+			break
+		}
+		key, ok := createKey(&rw.ops[i], path)
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		p := appendPath(path, key)
+		v, exists, err := rw.apply(p, nil, false, rw.ops[i:])
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err := writeKey(key); err != nil {
+				return err
+			}
+			if err := rw.write(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return rw.w.WriteByte('}')
+}
+
+// createKey returns the map key op creates in the map at path if it is
+// missing, and whether there is one.
+func createKey(op *RewriteOp, path []interface{}) (string, bool) {
+	n := len(op.Path)
+	if op.Kind != RewriteSet || n == 0 {
+		return "", false
+	}
+	key, ok := op.Path[n-1].(string)
+	if !ok || !matchPath(op.Path[:n-1], path) {
+		return "", false
+	}
+	return key, true
+}
+
+// array copies the array (whose opening delimiter is already read) at path
+// to the output, applying ops to its elements if active.
+func (rw *rewriter) array(path []interface{}, active bool) error {
+	if active && rw.shifts(path) {
+		// Later ops index the elements remaining after deletions, so the
+		// array is buffered:
+		s := []interface{}{}
+		for rw.dec.More() {
+			var v interface{}
+			if err := rw.dec.Decode(&v); err != nil {
+				return err
+			}
+			s = append(s, v)
+		}
+		if _, err := rw.dec.Token(); err != nil { // Closing ']'
+			return err
+		}
+		v, _, err := rw.apply(path, s, true, rw.ops)
+		if err != nil {
+			return err
+		}
+		return rw.write(v)
+	}
+
+	rw.w.WriteByte('[')
+	first := true
+	for idx := 0; rw.dec.More(); idx++ {
+		p := appendPath(path, idx)
+
+		if active && rw.matches(p) {
+			v, exists, err := rw.edit(p)
+			if err != nil {
+				return err
+			}
+			if exists {
+				if !first {
+					rw.w.WriteByte(',')
+				}
+				first = false
+				if err := rw.write(v); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !first {
+			rw.w.WriteByte(',')
+		}
+		first = false
+		if err := rw.stream(p, active); err != nil {
+			return err
+		}
+	}
+	if _, err := rw.dec.Token(); err != nil { // Closing ']'
+		return err
+	}
+	return rw.w.WriteByte(']')
+}
+
+// write writes v to the output as JSON.
+func (rw *rewriter) write(v interface{}) error {
+	rw.buf.Reset()
+	if err := rw.enc.Encode(jsonCompatible(v)); err != nil {
+		return err
+	}
+	_, err := rw.w.Write(bytes.TrimSuffix(rw.buf.Bytes(), []byte{'\n'}))
+	return err
+}
+
+// skip skips the next JSON value in the input.
+func (rw *rewriter) skip() error {
+	level := 0
+	for {
+		tok, err := rw.dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			if delim == '{' || delim == '[' {
+				level++
+			} else {
+				level--
+			}
+		}
+		if level == 0 {
+			return nil
+		}
+	}
+}
+
+// matchPath tells if the concrete path matches pattern, which may contain
+// the wildcards Any and AnyDeep.
+func matchPath(pattern, path []interface{}) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == AnyDeep {
+		for i := 0; i <= len(path); i++ {
+			if matchPath(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 || pattern[0] != Any && pattern[0] != path[0] {
+		return false
+	}
+	return matchPath(pattern[1:], path[1:])
+}
+
+// matchBelow tells if pattern, which may contain the wildcards Any and
+// AnyDeep, may match descendants of the concrete path.
+func matchBelow(pattern, path []interface{}) bool {
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == AnyDeep || len(path) == 0 {
+		return true
+	}
+	if pattern[0] != Any && pattern[0] != path[0] {
+		return false
+	}
+	return matchBelow(pattern[1:], path[1:])
+}
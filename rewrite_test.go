@@ -0,0 +1,173 @@
+package dyno
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	src := `{
+		"user": {"name": "bob", "password": "secret", "tags": ["a", "b"]},
+		"items": [
+			{"id": 1, "price": 1.50, "password": "x"},
+			{"id": 2, "price": 2e3}
+		],
+		"n": 12345678901234567890
+	}`
+
+	upper := func(path []interface{}, v interface{}) (interface{}, error) {
+		return strings.ToUpper(v.(string)), nil
+	}
+	same := func(path []interface{}, v interface{}) (interface{}, error) {
+		return v, nil
+	}
+	mask := func(path []interface{}, v interface{}) (interface{}, error) {
+		return "***", nil
+	}
+	count := func(path []interface{}, v interface{}) (interface{}, error) {
+		return len(v.([]interface{})), nil
+	}
+
+	cases := []struct {
+		title string      // Title of the test case
+		ops   []RewriteOp // Input operations
+		exp   string      // Expected output JSON
+		isErr bool        // Tells if error is expected
+	}{
+		// Test success:
+		{"no ops", nil,
+			`{"user":{"name":"bob","password":"secret","tags":["a","b"]},"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"mask passwords", []RewriteOp{{Kind: RewriteTransform, Path: []interface{}{AnyDeep, "password"}, Transform: mask}},
+			`{"user":{"name":"bob","password":"***","tags":["a","b"]},"items":[{"id":1,"price":1.50,"password":"***"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"set in all matching maps", []RewriteOp{{Kind: RewriteSet, Path: []interface{}{AnyDeep, "password"}, Value: "***"}},
+			`{"user":{"name":"bob","password":"***","tags":["a","b"]},"items":[{"id":1,"price":1.50,"password":"***"},{"id":2,"price":2e3,"password":"***"}],"n":12345678901234567890,"password":"***"}`, false},
+		{"create key with wildcard", []RewriteOp{{Kind: RewriteSet, Path: []interface{}{"items", Any, "price"}, Value: 0}, {Kind: RewriteSet, Path: []interface{}{"items", Any, "sale"}, Value: true}},
+			`{"user":{"name":"bob","password":"secret","tags":["a","b"]},"items":[{"id":1,"price":0,"password":"x","sale":true},{"id":2,"price":0,"sale":true}],"n":12345678901234567890}`, false},
+		{"delete", []RewriteOp{{Kind: RewriteDelete, Path: []interface{}{"user", "password"}}, {Kind: RewriteDelete, Path: []interface{}{"n"}}},
+			`{"user":{"name":"bob","tags":["a","b"]},"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}]}`, false},
+		{"delete first key", []RewriteOp{{Kind: RewriteDelete, Path: []interface{}{"user"}}},
+			`{"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"delete slice elements", []RewriteOp{{Kind: RewriteDelete, Path: []interface{}{"items", 0}}, {Kind: RewriteDelete, Path: []interface{}{"user", "tags", Any}}},
+			`{"user":{"name":"bob","password":"secret","tags":[]},"items":[{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"create key", []RewriteOp{{Kind: RewriteSet, Path: []interface{}{"user", "age"}, Value: 3}, {Kind: RewriteSet, Path: []interface{}{"x", "y"}, Value: 1}},
+			`{"user":{"name":"bob","password":"secret","tags":["a","b"],"age":3},"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"create key with wildcard in buffered value", []RewriteOp{{Kind: RewriteTransform, Path: []interface{}{"items"}, Transform: same}, {Kind: RewriteSet, Path: []interface{}{"items", Any, "sale"}, Value: true}},
+			`{"user":{"name":"bob","password":"secret","tags":["a","b"]},"items":[{"id":1,"password":"x","price":1.50,"sale":true},{"id":2,"price":2e3,"sale":true}],"n":12345678901234567890}`, false},
+		{"transform", []RewriteOp{{Kind: RewriteTransform, Path: []interface{}{"user", "name"}, Transform: upper}, {Kind: RewriteTransform, Path: []interface{}{"items", Any, "missing"}, Transform: upper}},
+			`{"user":{"name":"BOB","password":"secret","tags":["a","b"]},"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"wildcard over slice", []RewriteOp{{Kind: RewriteSet, Path: []interface{}{"items", Any, "id"}, Value: 0}},
+			`{"user":{"name":"bob","password":"secret","tags":["a","b"]},"items":[{"id":0,"price":1.50,"password":"x"},{"id":0,"price":2e3}],"n":12345678901234567890}`, false},
+		{"ops in order", []RewriteOp{
+			{Kind: RewriteTransform, Path: []interface{}{"user", "tags", 0}, Transform: upper},
+			{Kind: RewriteTransform, Path: []interface{}{"user", "tags"}, Transform: func(path []interface{}, v interface{}) (interface{}, error) {
+				return strings.Join([]string{v.([]interface{})[0].(string), v.([]interface{})[1].(string)}, "+"), nil
+			}}},
+			`{"user":{"name":"bob","password":"secret","tags":"A+b"},"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"ops below buffered value", []RewriteOp{
+			{Kind: RewriteTransform, Path: []interface{}{"user", "tags"}, Transform: count},
+			{Kind: RewriteDelete, Path: []interface{}{"user", "tags", 0}},
+			{Kind: RewriteSet, Path: []interface{}{"user", "tags"}, Value: []interface{}{"p", "q"}},
+			{Kind: RewriteDelete, Path: []interface{}{"user", "tags", 0}},
+			{Kind: RewriteTransform, Path: []interface{}{"user"}, Transform: func(path []interface{}, v interface{}) (interface{}, error) {
+				return v, Delete(v, "password")
+			}}},
+			`{"user":{"name":"bob","tags":["q"]},"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"index after delete", []RewriteOp{
+			{Kind: RewriteDelete, Path: []interface{}{"items", 0}},
+			{Kind: RewriteSet, Path: []interface{}{"items", 0, "sale"}, Value: true}},
+			`{"user":{"name":"bob","password":"secret","tags":["a","b"]},"items":[{"id":2,"price":2e3,"sale":true}],"n":12345678901234567890}`, false},
+		{"set then create", []RewriteOp{
+			{Kind: RewriteSet, Path: []interface{}{"user"}, Value: map[string]interface{}{}},
+			{Kind: RewriteSet, Path: []interface{}{"user", "a"}, Value: 1}},
+			`{"user":{"a":1},"items":[{"id":1,"price":1.50,"password":"x"},{"id":2,"price":2e3}],"n":12345678901234567890}`, false},
+		{"root", []RewriteOp{{Kind: RewriteTransform, Path: []interface{}{}, Transform: func(path []interface{}, v interface{}) (interface{}, error) {
+			return len(v.(map[string]interface{})), nil
+		}}}, `3`, false},
+
+		// Test errors:
+		{"transform error", []RewriteOp{{Kind: RewriteTransform, Path: []interface{}{"user"}, Transform: func(path []interface{}, v interface{}) (interface{}, error) {
+			return nil, errors.New("test")
+		}}}, "", true},
+		{"delete root error", []RewriteOp{{Kind: RewriteDelete}}, "", true},
+		{"trailing AnyDeep error", []RewriteOp{{Kind: RewriteSet, Path: []interface{}{"a", AnyDeep}}}, "", true},
+		{"missing transform error", []RewriteOp{{Kind: RewriteTransform, Path: []interface{}{"a"}}}, "", true},
+		{"invalid kind error", []RewriteOp{{Kind: RewriteKind(9), Path: []interface{}{"a"}}}, "", true},
+	}
+
+	for _, c := range cases {
+		var out bytes.Buffer
+		err := Rewrite(strings.NewReader(src), &out, c.ops...)
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+		if err != nil {
+			continue
+		}
+		if got := strings.TrimSuffix(out.String(), "\n"); got != c.exp {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, got)
+		}
+	}
+}
+
+func TestRewriteStream(t *testing.T) {
+	src := `{"msg": "a", "auth": {"token": "t1"}}
+{"msg": "b"}
+[{"token": "t2"}]
+"s"
+`
+	var out bytes.Buffer
+	err := Rewrite(strings.NewReader(src), &out, RewriteOp{Kind: RewriteDelete, Path: []interface{}{AnyDeep, "token"}})
+	exp := `{"msg":"a","auth":{}}
+{"msg":"b"}
+[{}]
+"s"
+`
+	if out.String() != exp || err != nil {
+		t.Errorf("Expected value: %v, got: %v, err value: %v", exp, out.String(), err)
+	}
+
+	// Invalid JSON:
+	for _, s := range []string{`{"a": [1, }`, `{"a": 1} ]`, `{"a"`} {
+		if err := Rewrite(strings.NewReader(s), &out, RewriteOp{Kind: RewriteDelete, Path: []interface{}{"x"}}); err == nil {
+			t.Errorf("Expected error for invalid JSON: %s", s)
+		}
+	}
+
+	// Unsupported values returned by transform:
+	err = Rewrite(strings.NewReader(`{"a": 1}`), &out, RewriteOp{Kind: RewriteTransform, Path: []interface{}{"a"},
+		Transform: func(path []interface{}, v interface{}) (interface{}, error) { return func() {}, nil }})
+	if _, ok := err.(*json.UnsupportedTypeError); !ok {
+		t.Errorf("Expected *json.UnsupportedTypeError, got: %v", err)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		title   string        // Title of the test case
+		pattern []interface{} // Input pattern
+		path    []interface{} // Input concrete path
+		match   bool          // Expected result of matchPath
+		below   bool          // Expected result of matchBelow
+	}{
+		{"equal", []interface{}{"a", 1}, []interface{}{"a", 1}, true, false},
+		{"prefix", []interface{}{"a", 1}, []interface{}{"a"}, false, true},
+		{"different", []interface{}{"a", 1}, []interface{}{"b"}, false, false},
+		{"any", []interface{}{Any, "x"}, []interface{}{0, "x"}, true, false},
+		{"any deep zero", []interface{}{AnyDeep, "x"}, []interface{}{"x"}, true, true},
+		{"any deep more", []interface{}{"a", AnyDeep, "x"}, []interface{}{"a", 1, "b", "x"}, true, true},
+		{"any deep no match", []interface{}{"a", AnyDeep, "x"}, []interface{}{"b", "x"}, false, false},
+		{"root", []interface{}{}, []interface{}{}, true, false},
+	}
+
+	for _, c := range cases {
+		if match := matchPath(c.pattern, c.path); match != c.match {
+			t.Errorf("[title: %s] Expected match: %v, got: %v", c.title, c.match, match)
+		}
+		if below := matchBelow(c.pattern, c.path); below != c.below {
+			t.Errorf("[title: %s] Expected below: %v, got: %v", c.title, c.below, below)
+		}
+	}
+}
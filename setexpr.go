@@ -0,0 +1,392 @@
+package dyno
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SetMode tells how ApplySet interprets values.
+type SetMode int
+
+const (
+	// SetTyped interprets values like Helm's --set: true and false are
+	// bools, decimal integers (without leading zeros) are int64 values,
+	// null removes the key, everything else is a string.
+	SetTyped SetMode = iota
+
+	// SetString interprets values like Helm's --set-string: all values
+	// are strings.
+	SetString
+
+	// SetJSON interprets values like Helm's --set-json: values are JSON
+	// texts (null removes the key).
+	SetJSON
+)
+
+// String returns the name of the flag corresponding to the mode.
+func (m SetMode) String() string {
+	switch m {
+	case SetTyped:
+		return "set"
+	case SetString:
+		return "set-string"
+	case SetJSON:
+		return "set-json"
+	}
+	return fmt.Sprintf("SetMode(%d)", int(m))
+}
+
+// ApplySet applies the Helm-style --set expression expr to v, which must be
+// a map (map[string]interface{} or map[interface{}]interface{}).
+//
+// expr is a comma separated list of assignments in the form of key=value,
+// e.g. "a.b[0].c=1,x.y={1,2}". Keys are dot separated map keys, optionally
+// followed by slice indices in brackets. Values are interpreted according
+// to mode; in SetTyped and SetString modes a value in braces is a list,
+// e.g. {a,b}, whose elements are interpreted the same way. A backslash
+// escapes the next character (e.g. "\," or "\."), both in keys and values.
+//
+// Missing maps and slices on the way are created (maps having the same key
+// type as their parent), slices are extended as needed (new elements being
+// nil, indices cannot exceed 65536). Existing maps, Containers and (with
+// ReflectFallback) reflected maps, slices, arrays and structs are updated in
+// place (reflected slices are extended with zero values), existing values
+// of another kind are replaced.
+//
+// The whole expression is parsed first, so if it is invalid, v is not
+// modified. If an assignment fails (e.g. a value cannot be assigned to a
+// typed element), the error is returned, and the assignments preceding it
+// remain applied.
+func ApplySet(v interface{}, expr string, mode SetMode) error {
+	switch v.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+	default:
+		return fmt.Errorf("expected map, got: %T", v)
+	}
+
+	assigns, err := parseSetExpr(expr, mode)
+	if err != nil {
+		return err
+	}
+	for _, a := range assigns {
+		if _, err := setCreate(v, a.path, a.value, a.remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetFlag is a flag.Value that applies the values of the flag to Target
+// using ApplySet. It can be used multiple times on the command line, e.g.
+//
+//	sets := &dyno.SetFlag{}
+//	flag.Var(sets, "set", "set values (can be repeated): key1=val1,key2=val2")
+type SetFlag struct {
+	// Target to apply values to. If nil, it is initialized with an empty
+	// map[string]interface{} when the first value is set.
+	Target interface{}
+
+	// Mode of interpreting values.
+	Mode SetMode
+
+	exprs []string // Expressions set so far
+}
+
+// String returns the expressions set so far, separated by commas.
+func (f *SetFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.exprs, ",")
+}
+
+// Set applies expr to Target.
+func (f *SetFlag) Set(expr string) error {
+	if f.Target == nil {
+		f.Target = map[string]interface{}{}
+	}
+	if err := ApplySet(f.Target, expr, f.Mode); err != nil {
+		return err
+	}
+	f.exprs = append(f.exprs, expr)
+	return nil
+}
+
+// maxSetIndex is the max slice index allowed in set expressions (like in
+// Helm), so a single expression cannot allocate huge slices.
+const maxSetIndex = 65536
+
+// setAssign is a parsed assignment of a set expression.
+type setAssign struct {
+	path   []interface{} // Path of the key
+	value  interface{}   // Value to set
+	remove bool          // Tells if the key is to be removed (null value)
+}
+
+// setParser parses set expressions.
+type setParser struct {
+	s    string  // The expression
+	pos  int     // Current position
+	mode SetMode // Mode of interpreting values
+}
+
+// parseSetExpr parses the set expression.
+func parseSetExpr(expr string, mode SetMode) ([]setAssign, error) {
+	p := &setParser{s: expr, mode: mode}
+
+	var assigns []setAssign
+	for p.pos < len(p.s) {
+		a, err := p.assign()
+		if err != nil {
+			return nil, fmt.Errorf("invalid set expression %q: %v", expr, err)
+		}
+		assigns = append(assigns, a)
+	}
+	return assigns, nil
+}
+
+// assign parses an assignment and the comma following it (if any).
+func (p *setParser) assign() (a setAssign, err error) {
+	if a.path, err = p.key(); err != nil {
+		return
+	}
+
+	if p.mode == SetJSON {
+		err = p.jsonValue(&a)
+	} else if p.pos < len(p.s) && p.s[p.pos] == '{' {
+		p.pos++
+		a.value, err = p.list()
+	} else {
+		var s string
+		s, _ = p.until(",")
+		a.value, a.remove = p.typed(s)
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	// A comma or the end must follow:
+	if p.pos < len(p.s) {
+		if p.s[p.pos] != ',' {
+			return a, fmt.Errorf("expected ',' at position %d", p.pos)
+		}
+		p.pos++
+	}
+	return
+}
+
+// key parses a key and the '=' following it.
+func (p *setParser) key() ([]interface{}, error) {
+	var path []interface{}
+	for {
+		seg, stop := p.until(".[=,")
+		switch {
+		case seg != "":
+			path = append(path, seg)
+		case stop != '[' || len(path) == 0:
+			return nil, fmt.Errorf("empty key at position %d", p.pos)
+		}
+
+		// Indices:
+		for stop == '[' {
+			end := strings.IndexByte(p.s[p.pos:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ']' at position %d", p.pos)
+			}
+			idx, err := strconv.Atoi(p.s[p.pos : p.pos+end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid index %q at position %d", p.s[p.pos:p.pos+end], p.pos)
+			}
+			if idx > maxSetIndex {
+				return nil, fmt.Errorf("index %d exceeds max %d at position %d", idx, maxSetIndex, p.pos)
+			}
+			path = append(path, idx)
+			p.pos += end + 1
+			stop = 0
+			if p.pos < len(p.s) {
+				stop = p.s[p.pos]
+				p.pos++
+			}
+		}
+
+		switch stop {
+		case '.':
+			continue
+		case '=':
+			return path, nil
+		}
+		return nil, fmt.Errorf("key has no value at position %d", p.pos)
+	}
+}
+
+// until returns the unescaped text until one of the stop characters or
+// the end. The position is moved after the stop character which is also
+// returned (0 if the end is reached).
+func (p *setParser) until(stops string) (string, byte) {
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == '\\' && p.pos < len(p.s):
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		case strings.IndexByte(stops, c) >= 0:
+			return b.String(), c
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), 0
+}
+
+// list parses a list literal (after the opening brace).
+func (p *setParser) list() ([]interface{}, error) {
+	list := []interface{}{}
+	if strings.HasPrefix(p.s[p.pos:], "}") {
+		p.pos++
+		return list, nil
+	}
+	for {
+		s, stop := p.until(",}")
+		v, _ := p.typed(s)
+		list = append(list, v)
+		switch stop {
+		case '}':
+			return list, nil
+		case 0:
+			return nil, fmt.Errorf("missing '}'")
+		}
+	}
+}
+
+// typed returns the value of s according to the mode. The second return
+// value tells if s denotes null.
+func (p *setParser) typed(s string) (interface{}, bool) {
+	if p.mode == SetString {
+		return s, false
+	}
+
+	switch s {
+	case "true":
+		return true, false
+	case "false":
+		return false, false
+	case "null":
+		return nil, true
+	}
+	if s != "" && (s == "0" || s[0] != '0' && !strings.HasPrefix(s, "-0")) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, false
+		}
+	}
+	return s, false
+}
+
+// jsonValue parses a JSON value.
+func (p *setParser) jsonValue(a *setAssign) error {
+	r := strings.NewReader(p.s[p.pos:])
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&a.value); err != nil {
+		return fmt.Errorf("invalid JSON value at position %d: %v", p.pos, err)
+	}
+	a.remove = a.value == nil
+
+	// Position after the JSON value: what's not yet read or buffered.
+	buffered, _ := io.Copy(ioutil.Discard, dec.Buffered())
+	p.pos = len(p.s) - r.Len() - int(buffered)
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	return nil
+}
+
+// setCreate sets value in node at path, creating missing maps and slices
+// and extending slices as needed. If remove is true, the map key denoted by
+// path is deleted (or the slice element is set to nil) instead.
+// The new node is returned (which differs from node if it had to be
+// created, replaced or extended).
+func setCreate(node interface{}, path []interface{}, value interface{}, remove bool) (interface{}, error) {
+	el := path[0]
+
+	// Ensure node is of the right kind:
+	if idx, ok := el.(int); ok {
+		if rv, ok := reflectSlice(node); ok {
+			// Updated in place, slices extended with zero values as needed:
+			if idx >= rv.Len() && rv.Kind() == reflect.Slice {
+				if remove {
+					return node, nil
+				}
+				n := idx + 1 - rv.Len()
+				ext := reflect.AppendSlice(rv, reflect.MakeSlice(rv.Type(), n, n))
+				if rv.CanSet() {
+					rv.Set(ext)
+				} else {
+					node = ext.Interface()
+				}
+			}
+		} else {
+			s, ok := node.([]interface{})
+			if !ok {
+				s = []interface{}{}
+			}
+			if idx >= len(s) {
+				if remove {
+					return s, nil
+				}
+				s = append(s, make([]interface{}, idx+1-len(s))...)
+			}
+			node = s
+		}
+	} else if !isMapNode(node, el) {
+		node = map[string]interface{}{}
+	}
+
+	if len(path) == 1 {
+		if _, isIdx := el.(int); remove && !isIdx {
+			return node, Delete(node, el)
+		}
+		return node, setElem(node, el, 0, value)
+	}
+
+	child, _, err := getElem(node, el, 0)
+	if err != nil {
+		return nil, err
+	}
+	if _, isIdx := path[1].(int); !isIdx && !isMapNode(child, path[1]) {
+		if _, ok := node.(map[interface{}]interface{}); ok {
+			child = map[interface{}]interface{}{}
+		}
+	}
+	newChild, err := setCreate(child, path[1:], value, remove)
+	if err != nil {
+		return nil, err
+	}
+	return node, setElem(node, el, 0, newChild)
+}
+
+// reflectSlice returns node as a reflect.Value if it is a reflected slice
+// or array (with ReflectFallback).
+func reflectSlice(node interface{}) (reflect.Value, bool) {
+	if _, ok := node.([]interface{}); ok {
+		return reflect.Value{}, false
+	}
+	rv, ok := reflectNode(node)
+	return rv, ok && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array)
+}
+
+// isMapNode tells if node can hold the map key el: if it is a map, a
+// Container or (with ReflectFallback) a reflected map or struct.
+func isMapNode(node, el interface{}) bool {
+	if _, ok := node.([]interface{}); ok {
+		return false
+	}
+	_, _, err := getElem(node, el, 0)
+	return err == nil
+}
@@ -0,0 +1,238 @@
+package dyno
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestApplySet(t *testing.T) {
+	cases := []struct {
+		title string      // Title of the test case
+		v     string      // Input dynamic object in JSON
+		expr  string      // Set expression
+		mode  SetMode     // Mode of interpreting values
+		exp   interface{} // Expected result
+		isErr bool        // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "typed values",
+			v:     `{}`,
+			expr:  "a=1,b=true,c=false,d=x,e=007,f=-3,g=1.5,h=",
+			exp: map[string]interface{}{
+				"a": int64(1), "b": true, "c": false, "d": "x", "e": "007", "f": int64(-3), "g": "1.5", "h": "",
+			},
+		},
+		{
+			title: "nested keys and indices",
+			v:     `{"a": {"x": 1}}`,
+			expr:  "a.b[1].c=v,a.d[0][1]=w",
+			exp: map[string]interface{}{"a": map[string]interface{}{
+				"x": 1.0,
+				"b": []interface{}{nil, map[string]interface{}{"c": "v"}},
+				"d": []interface{}{[]interface{}{nil, "w"}},
+			}},
+		},
+		{
+			title: "existing slice is extended",
+			v:     `{"s": [1, {"k": 2}]}`,
+			expr:  "s[1].k=3,s[2]=4",
+			exp:   map[string]interface{}{"s": []interface{}{1.0, map[string]interface{}{"k": int64(3)}, int64(4)}},
+		},
+		{
+			title: "list literals",
+			v:     `{}`,
+			expr:  "a={1,x,true},b={},c={a\\,b}",
+			exp: map[string]interface{}{
+				"a": []interface{}{int64(1), "x", true}, "b": []interface{}{}, "c": []interface{}{"a,b"},
+			},
+		},
+		{
+			title: "escapes",
+			v:     `{}`,
+			expr:  `a\.b=x\,y,c\=d=e`,
+			exp:   map[string]interface{}{"a.b": "x,y", "c=d": "e"},
+		},
+		{
+			title: "null removes key",
+			v:     `{"a": 1, "b": {"c": 2, "d": 3}}`,
+			expr:  "a=null,b.c=null",
+			exp:   map[string]interface{}{"b": map[string]interface{}{"d": 3.0}},
+		},
+		{
+			title: "leaf replaced by map",
+			v:     `{"a": 1}`,
+			expr:  "a.b=2",
+			exp:   map[string]interface{}{"a": map[string]interface{}{"b": int64(2)}},
+		},
+		{
+			title: "string mode",
+			v:     `{}`,
+			expr:  "a=1,b=true,c=null,d={1,2}",
+			mode:  SetString,
+			exp: map[string]interface{}{
+				"a": "1", "b": "true", "c": "null", "d": []interface{}{"1", "2"},
+			},
+		},
+		{
+			title: "JSON mode",
+			v:     `{"x": 1}`,
+			expr:  `a={"b": [1, "c,d"]} ,e=[true],f="g",x=null`,
+			mode:  SetJSON,
+			exp: map[string]interface{}{
+				"a": map[string]interface{}{"b": []interface{}{1.0, "c,d"}}, "e": []interface{}{true}, "f": "g",
+			},
+		},
+
+		// Test errors:
+		{
+			title: "missing value error",
+			v:     `{"a": 1}`,
+			expr:  "a=2,b",
+			exp:   map[string]interface{}{"a": 1.0},
+			isErr: true,
+		},
+		{
+			title: "empty key error",
+			v:     `{}`,
+			expr:  "a..b=1",
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+		{
+			title: "root index error",
+			v:     `{}`,
+			expr:  "[0]=1",
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+		{
+			title: "invalid index error",
+			v:     `{}`,
+			expr:  "a[x]=1",
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+		{
+			title: "index too large error",
+			v:     `{}`,
+			expr:  "a[999999999]=1",
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+		{
+			title: "unclosed list error",
+			v:     `{}`,
+			expr:  "a={1,2",
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+		{
+			title: "invalid JSON error",
+			v:     `{}`,
+			expr:  `a={"b"}`,
+			mode:  SetJSON,
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+		{
+			title: "non-map root error",
+			v:     `[]`,
+			expr:  "a=1",
+			exp:   []interface{}{},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		v := decodeJSON(c.v)
+		err := ApplySet(v, c.expr, c.mode)
+		if !reflect.DeepEqual(v, c.exp) {
+			got, _ := json.Marshal(v)
+			t.Errorf("[title: %s] Expected value: %v, got: %s", c.title, c.exp, got)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	// Maps created under a map with interface{} keys have interface{} keys:
+	v := map[interface{}]interface{}{}
+	if err := ApplySet(v, "a.b=1", SetTyped); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, ok := v["a"].(map[interface{}]interface{}); !ok {
+		t.Errorf("Expected map[interface{}]interface{}, got: %T", v["a"])
+	}
+}
+
+func TestApplySetExisting(t *testing.T) {
+	om := NewOrderedMap()
+	om.Set("keep", 1)
+	v := map[string]interface{}{"a": om}
+	if err := ApplySet(v, "a.b=2,a.c.d=3,a.keep=null", SetTyped); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if v["a"] != om {
+		t.Errorf("Expected ordered map to be kept, got: %T", v["a"])
+	}
+	if data, _ := json.Marshal(v); string(data) != `{"a":{"b":2,"c":{"d":3}}}` {
+		t.Errorf("Expected value: %s, got: %s", `{"a":{"b":2,"c":{"d":3}}}`, data)
+	}
+
+	defer enableReflect()()
+	labels := map[string]string{"keep": "1"}
+	v = map[string]interface{}{"labels": labels}
+	if err := ApplySet(v, "labels.b=2", SetString); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if exp := map[string]string{"keep": "1", "b": "2"}; !reflect.DeepEqual(v["labels"], exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, v["labels"])
+	}
+	v = map[string]interface{}{"a": []string{"p", "q"}}
+	if err := ApplySet(v, "a[1]=x,a[3]=y", SetString); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if exp := []string{"p", "x", "", "y"}; !reflect.DeepEqual(v["a"], exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, v["a"])
+	}
+
+	// Failing assignment leaves preceding assignments applied:
+	if err := ApplySet(v, "a[0]=z,a[1]=2", SetTyped); err == nil {
+		t.Errorf("Expected error")
+	}
+	if exp := []string{"z", "x", "", "y"}; !reflect.DeepEqual(v["a"], exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, v["a"])
+	}
+}
+
+func TestSetFlag(t *testing.T) {
+	sets := &SetFlag{}
+	jsons := &SetFlag{Mode: SetJSON}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Var(sets, "set", "set values")
+	fs.Var(jsons, "set-json", "set JSON values")
+
+	err := fs.Parse([]string{"-set", "a=1,b.c=x", "-set", "b.d=true", "-set-json", `e={"f":1}`})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	exp := map[string]interface{}{"a": int64(1), "b": map[string]interface{}{"c": "x", "d": true}}
+	if !reflect.DeepEqual(sets.Target, exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, sets.Target)
+	}
+	if s := sets.String(); s != "a=1,b.c=x,b.d=true" {
+		t.Errorf("Expected string: %q, got: %q", "a=1,b.c=x,b.d=true", s)
+	}
+	if exp := decodeJSON(`{"e": {"f": 1}}`); !reflect.DeepEqual(jsons.Target, exp) {
+		t.Errorf("Expected value: %v, got: %v", exp, jsons.Target)
+	}
+
+	if err := fs.Parse([]string{"-set", "a"}); err == nil {
+		t.Errorf("Expected error for invalid expression")
+	}
+}
@@ -0,0 +1,287 @@
+package dyno
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is a secondary sort key of SortSlice.
+type SortKey struct {
+	// Path of the sort key inside the slice elements.
+	// If empty or nil, the elements themselves are used.
+	Path []interface{}
+
+	// Desc tells to sort in descending order.
+	Desc bool
+}
+
+// SortOpts holds options for SortSlice.
+type SortOpts struct {
+	// Desc tells to sort in descending order by the primary key.
+	Desc bool
+
+	// Natural tells to compare strings in natural order, that is, runs of
+	// decimal digits are compared by their numeric value,
+	// e.g. "a2" < "a10".
+	Natural bool
+
+	// ThenBy lists the secondary sort keys, used if elements are equal
+	// by the primary key.
+	ThenBy []SortKey
+}
+
+// SortSlice sorts the slice denoted by path by the value denoted by byPath
+// inside each element. If byPath is empty or nil, the elements themselves
+// are compared. opts may be nil, in which case the zero value of SortOpts
+// is used.
+//
+// Values are ordered as follows: missing values and nil come first, then
+// bools (false before true), numbers (by value, regardless of their types),
+// strings, time.Time values, and finally all other values (ordered by their
+// type names and string representations).
+//
+// The sort is stable and is done in place. If path is empty or nil, v itself
+// is sorted.
+func SortSlice(v interface{}, byPath []interface{}, opts *SortOpts, path ...interface{}) error {
+	if opts == nil {
+		opts = &SortOpts{}
+	}
+
+	s, err := getSlice(v, path)
+	if err != nil {
+		return err
+	}
+
+	keys := append([]SortKey{{Path: byPath, Desc: opts.Desc}}, opts.ThenBy...)
+
+	// Extract sort values once:
+	values := make([][]interface{}, len(s))
+	for i, el := range s {
+		values[i] = make([]interface{}, len(keys))
+		for j, key := range keys {
+			if value, err := Get(el, key.Path...); err == nil {
+				values[i][j] = value
+			} else {
+				values[i][j] = missing{}
+			}
+		}
+	}
+
+	idx := make([]int, len(s))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		a, b := values[idx[i]], values[idx[j]]
+		for k, key := range keys {
+			c := compareSortValues(a[k], b[k], opts.Natural)
+			if key.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	sorted := make([]interface{}, len(s))
+	for i, j := range idx {
+		sorted[i] = s[j]
+	}
+	copy(s, sorted)
+
+	return nil
+}
+
+// Unique removes duplicate elements from the slice denoted by path, keeping
+// the first occurrence of each element.
+//
+// If keyPath is empty or nil, elements are compared using Equal. Else
+// elements are considered duplicates if the values denoted by keyPath inside
+// them are equal (as defined by Equal); elements missing the key are
+// always kept.
+//
+// Path cannot be empty or nil, else an error is returned.
+func Unique(v interface{}, keyPath []interface{}, path ...interface{}) error {
+	if len(path) == 0 {
+		return fmt.Errorf("path cannot be empty")
+	}
+
+	s, err := getSlice(v, path)
+	if err != nil {
+		return err
+	}
+
+	res := make([]interface{}, 0, len(s))
+	var seen []interface{}
+outer:
+	for _, el := range s {
+		key, err := Get(el, keyPath...)
+		if err == nil {
+			for _, k := range seen {
+				if Equal(k, key) {
+					continue outer
+				}
+			}
+			seen = append(seen, key)
+		}
+		res = append(res, el)
+	}
+
+	// Must set the new slice value:
+	return Set(v, res, path...)
+}
+
+// Reverse reverses the order of the elements of the slice denoted by path.
+//
+// The slice is reversed in place. If path is empty or nil, v itself is
+// reversed.
+func Reverse(v interface{}, path ...interface{}) error {
+	s, err := getSlice(v, path)
+	if err != nil {
+		return err
+	}
+
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+	return nil
+}
+
+// getSlice returns the slice denoted by path.
+func getSlice(v interface{}, path []interface{}) ([]interface{}, error) {
+	node, err := Get(v, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected slice node, got: %T (path element idx: %d)", node, len(path)-1)
+	}
+	return s, nil
+}
+
+// missing denotes a missing sort value.
+type missing struct{}
+
+// sortRank returns the rank of the kind of value in the sort order.
+func sortRank(v interface{}) int {
+	switch v.(type) {
+	case missing:
+		return 0
+	case nil:
+		return 1
+	case bool:
+		return 2
+	case string:
+		return 4
+	case time.Time:
+		return 5
+	}
+	if isNumber(v) {
+		return 3
+	}
+	return 6
+}
+
+// compareSortValues compares a and b by the order documented at SortSlice.
+func compareSortValues(a, b interface{}, natural bool) int {
+	ra, rb := sortRank(a), sortRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+
+	switch x := a.(type) {
+	case bool:
+		y := b.(bool)
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case string:
+		if natural {
+			return compareNatural(x, b.(string))
+		}
+		return strings.Compare(x, b.(string))
+	case time.Time:
+		c, _ := compareValues(x, b)
+		return c
+	}
+
+	if ra == 3 {
+		c, _ := compareNumbers(a, b)
+		return c
+	}
+
+	switch {
+	case lessKey(a, b):
+		return -1
+	case lessKey(b, a):
+		return 1
+	}
+	return 0
+}
+
+// compareNatural compares strings in natural order: runs of decimal digits
+// are compared by their numeric value.
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			da, db := digitPrefix(a), digitPrefix(b)
+			a, b = a[len(da):], b[len(db):]
+			// Compare by value: ignore leading zeros, longer is greater:
+			ta, tb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if len(ta) != len(tb) {
+				if len(ta) < len(tb) {
+					return -1
+				}
+				return 1
+			}
+			if c := strings.Compare(ta, tb); c != 0 {
+				return c
+			}
+			// Same value: fewer leading zeros comes first:
+			if len(da) != len(db) {
+				if len(da) < len(db) {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return strings.Compare(a, b)
+}
+
+// isDigit tells if c is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// digitPrefix returns the leading run of decimal digits of s.
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i]
+}
@@ -0,0 +1,159 @@
+package dyno
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSortSlice(t *testing.T) {
+	cases := []struct {
+		title  string        // Title of the test case
+		v      string        // Input dynamic object in JSON
+		byPath []interface{} // Path of the sort key inside elements
+		opts   *SortOpts     // Sort options
+		path   []interface{} // Path of the slice
+		exp    string        // Expected result in JSON
+		isErr  bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "numbers ascending",
+			v:     `{"a": [3, 1.5, 2, 10]}`,
+			path:  []interface{}{"a"},
+			exp:   `{"a": [1.5, 2, 3, 10]}`,
+		},
+		{
+			title: "root slice descending",
+			v:     `[3, 1, 2]`,
+			opts:  &SortOpts{Desc: true},
+			exp:   `[3, 2, 1]`,
+		},
+		{
+			title:  "by nested field, missing first",
+			v:      `{"a": [{"n": {"x": 2}}, {"n": {}}, {"n": {"x": 1}}]}`,
+			byPath: []interface{}{"n", "x"},
+			path:   []interface{}{"a"},
+			exp:    `{"a": [{"n": {}}, {"n": {"x": 1}}, {"n": {"x": 2}}]}`,
+		},
+		{
+			title: "mixed kinds",
+			v:     `["b", 2, true, null, "a", 1, false]`,
+			exp:   `[null, false, true, 1, 2, "a", "b"]`,
+		},
+		{
+			title: "natural strings",
+			v:     `["a10", "a2", "a02", "b", "a1"]`,
+			opts:  &SortOpts{Natural: true},
+			exp:   `["a1", "a2", "a02", "a10", "b"]`,
+		},
+		{
+			title: "lexical strings",
+			v:     `["a10", "a2", "a1"]`,
+			exp:   `["a1", "a10", "a2"]`,
+		},
+		{
+			title:  "multi-key",
+			v:      `[{"g": "x", "n": 1}, {"g": "y", "n": 2}, {"g": "x", "n": 3}]`,
+			byPath: []interface{}{"g"},
+			opts:   &SortOpts{ThenBy: []SortKey{{Path: []interface{}{"n"}, Desc: true}}},
+			exp:    `[{"g": "x", "n": 3}, {"g": "x", "n": 1}, {"g": "y", "n": 2}]`,
+		},
+
+		// Test errors:
+		{
+			title: "non-slice error",
+			v:     `{"a": 1}`,
+			path:  []interface{}{"a"},
+			exp:   `{"a": 1}`,
+			isErr: true,
+		},
+		{
+			title: "missing path error",
+			v:     `{"a": 1}`,
+			path:  []interface{}{"x"},
+			exp:   `{"a": 1}`,
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		v := decodeJSON(c.v)
+		err := SortSlice(v, c.byPath, c.opts, c.path...)
+		if !Equal(v, decodeJSON(c.exp)) {
+			got, _ := json.Marshal(v)
+			t.Errorf("[title: %s] Expected value: %s, got: %s", c.title, c.exp, got)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	cases := []struct {
+		title   string        // Title of the test case
+		v       string        // Input dynamic object in JSON
+		keyPath []interface{} // Path of the key inside elements
+		path    []interface{} // Path of the slice
+		exp     string        // Expected result in JSON
+		isErr   bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "structural equality",
+			v:     `{"a": [1, {"x": 1}, 1, {"x": 1}, 2]}`,
+			path:  []interface{}{"a"},
+			exp:   `{"a": [1, {"x": 1}, 2]}`,
+		},
+		{
+			title:   "by key, missing keys kept",
+			v:       `{"a": [{"id": 1, "v": "a"}, {"v": "b"}, {"id": 1, "v": "c"}, {"v": "d"}]}`,
+			keyPath: []interface{}{"id"},
+			path:    []interface{}{"a"},
+			exp:     `{"a": [{"id": 1, "v": "a"}, {"v": "b"}, {"v": "d"}]}`,
+		},
+
+		// Test errors:
+		{
+			title: "empty path error",
+			v:     `[1, 1]`,
+			exp:   `[1, 1]`,
+			isErr: true,
+		},
+		{
+			title: "non-slice error",
+			v:     `{"a": "x"}`,
+			path:  []interface{}{"a"},
+			exp:   `{"a": "x"}`,
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		v := decodeJSON(c.v)
+		err := Unique(v, c.keyPath, c.path...)
+		if !Equal(v, decodeJSON(c.exp)) {
+			got, _ := json.Marshal(v)
+			t.Errorf("[title: %s] Expected value: %s, got: %s", c.title, c.exp, got)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	v := decodeJSON(`{"a": [1, 2, 3]}`)
+	if err := Reverse(v, "a"); err != nil || !Equal(v, decodeJSON(`{"a": [3, 2, 1]}`)) {
+		t.Errorf("Expected reversed slice, got: %v, err value: %v", v, err)
+	}
+
+	v = decodeJSON(`[1, 2]`)
+	if err := Reverse(v); err != nil || !Equal(v, decodeJSON(`[2, 1]`)) {
+		t.Errorf("Expected reversed root slice, got: %v, err value: %v", v, err)
+	}
+
+	if err := Reverse(decodeJSON(`{"a": 1}`), "a"); err == nil {
+		t.Errorf("Expected error for non-slice node")
+	}
+}
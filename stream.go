@@ -0,0 +1,246 @@
+package dyno
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// GetFromReader returns the value denoted by the path from the JSON value
+// read from r, without unmarshaling the whole JSON value: subtrees not on
+// the path are skipped, and only the denoted value is unmarshaled (into a
+// dynamic object, like json.Unmarshal would into an interface{} value).
+//
+// Path elements follow the rules of Get: string keys for JSON objects and
+// int indices for JSON arrays. Reading stops as soon as the value is found,
+// so the rest of r is not read (nor validated); this also means that if an
+// object has duplicate keys, the first one is used.
+//
+// If path is empty or nil, the whole JSON value is returned.
+func GetFromReader(r io.Reader, path ...interface{}) (interface{}, error) {
+	values, errs, err := GetManyFromReader(r, path)
+	if err != nil {
+		return nil, err
+	}
+	return values[0], errs[0]
+}
+
+// GetManyFromReader is like GetFromReader, but it returns the values
+// denoted by multiple paths, reading r only once.
+//
+// The returned values and errs are parallel to paths: if the value denoted
+// by a path cannot be found, its value is nil and its error tells why (just
+// like Get would). err is non-nil only if r cannot be read or contains
+// invalid JSON.
+//
+// Reading stops as soon as all values are found.
+func GetManyFromReader(r io.Reader, paths ...[]interface{}) (values []interface{}, errs []error, err error) {
+	sps := make([]*streamPath, len(paths))
+	for i, path := range paths {
+		sps[i] = &streamPath{path: path}
+	}
+
+	g := &streamGetter{dec: json.NewDecoder(r), pending: len(sps)}
+	if err := g.value(sps, 0); err != nil && err != errStreamDone {
+		return nil, nil, err
+	}
+
+	values, errs = make([]interface{}, len(sps)), make([]error, len(sps))
+	for i, sp := range sps {
+		values[i], errs[i] = sp.value, sp.err
+	}
+	return values, errs, nil
+}
+
+// errStreamDone is returned internally when all paths are resolved.
+var errStreamDone = errors.New("done")
+
+// streamPath is a path to get from a JSON stream.
+type streamPath struct {
+	path  []interface{}
+	value interface{} // Value found at path
+	err   error       // Error if the value cannot be found
+	done  bool        // Tells if the path is resolved (value or err is set)
+}
+
+// streamGetter gets the values of paths from a JSON stream.
+type streamGetter struct {
+	dec     *json.Decoder
+	pending int // Number of unresolved paths
+}
+
+// resolve resolves sp with the given value and error.
+// Returns errStreamDone if no unresolved paths remain.
+func (g *streamGetter) resolve(sp *streamPath, value interface{}, err error) error {
+	sp.value, sp.err, sp.done = value, err, true
+	if g.pending--; g.pending == 0 {
+		return errStreamDone
+	}
+	return nil
+}
+
+// value processes the next JSON value in the stream, sps are the
+// unresolved paths going through it, depth is its depth.
+func (g *streamGetter) value(sps []*streamPath, depth int) error {
+	if len(sps) == 0 {
+		return g.skip()
+	}
+
+	for _, sp := range sps {
+		if len(sp.path) == depth {
+			return g.decode(sps, depth)
+		}
+	}
+
+	tok, err := g.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		for _, sp := range sps {
+			if err := g.resolve(sp, nil, fmt.Errorf("expected map or slice node, got: %T (path element idx: %d)", tok, depth)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if delim == '{' {
+		return g.object(sps, depth)
+	}
+	return g.array(sps, depth)
+}
+
+// decode decodes the next JSON value in the stream, and resolves all sps
+// from it.
+func (g *streamGetter) decode(sps []*streamPath, depth int) error {
+	var v interface{}
+	if err := g.dec.Decode(&v); err != nil {
+		return err
+	}
+
+	for _, sp := range sps {
+		node, err := v, error(nil)
+		for i := depth; i < len(sp.path) && err == nil; i++ {
+			var exists bool
+			if node, exists, err = getElem(node, sp.path[i], i); err == nil && !exists {
+				err = fmt.Errorf("missing key: %v (path element idx: %d)", sp.path[i], i)
+			}
+		}
+		if err != nil {
+			node = nil
+		}
+		if err := g.resolve(sp, node, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// object processes the JSON object whose opening delimiter is already read.
+func (g *streamGetter) object(sps []*streamPath, depth int) error {
+	var active []*streamPath
+	for _, sp := range sps {
+		if _, ok := sp.path[depth].(string); !ok {
+			err := fmt.Errorf("expected string path element, got: %T (path element idx: %d)", sp.path[depth], depth)
+			if err := g.resolve(sp, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+		active = append(active, sp)
+	}
+
+	for g.dec.More() {
+		tok, err := g.dec.Token()
+		if err != nil {
+			return err
+		}
+		var next []*streamPath
+		for _, sp := range active {
+			if !sp.done && sp.path[depth] == tok {
+				next = append(next, sp)
+			}
+		}
+		if err := g.value(next, depth+1); err != nil {
+			return err
+		}
+	}
+	if _, err := g.dec.Token(); err != nil { // Closing '}'
+		return err
+	}
+
+	for _, sp := range active {
+		if !sp.done {
+			err := fmt.Errorf("missing key: %v (path element idx: %d)", sp.path[depth], depth)
+			if err := g.resolve(sp, nil, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// array processes the JSON array whose opening delimiter is already read.
+func (g *streamGetter) array(sps []*streamPath, depth int) error {
+	var active []*streamPath
+	for _, sp := range sps {
+		if _, ok := sp.path[depth].(int); !ok {
+			err := fmt.Errorf("expected int path element, got: %T (path element idx: %d)", sp.path[depth], depth)
+			if err := g.resolve(sp, nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+		active = append(active, sp)
+	}
+
+	for idx := 0; g.dec.More(); idx++ {
+		var next []*streamPath
+		for _, sp := range active {
+			if !sp.done && sp.path[depth] == idx {
+				next = append(next, sp)
+			}
+		}
+		if err := g.value(next, depth+1); err != nil {
+			return err
+		}
+	}
+	if _, err := g.dec.Token(); err != nil { // Closing ']'
+		return err
+	}
+
+	for _, sp := range active {
+		if !sp.done {
+			err := fmt.Errorf("index out of range: %d (path element idx: %d)", sp.path[depth], depth)
+			if err := g.resolve(sp, nil, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skip skips the next JSON value in the stream.
+func (g *streamGetter) skip() error {
+	level := 0
+	for {
+		tok, err := g.dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			if delim == '{' || delim == '[' {
+				level++
+			} else {
+				level--
+			}
+		}
+		if level == 0 {
+			return nil
+		}
+	}
+}
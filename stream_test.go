@@ -0,0 +1,88 @@
+package dyno
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetFromReader(t *testing.T) {
+	src := `{
+		"meta": {"count": 2, "tags": ["a", "b"]},
+		"items": [
+			{"id": 1, "name": "x", "attrs": {"deep": [1, {"k": null}]}},
+			{"id": 2, "name": "y"}
+		],
+		"last": true
+	}`
+
+	cases := []struct {
+		title string        // Title of the test case
+		path  []interface{} // Input path
+		value interface{}   // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{"root", []interface{}{}, decodeJSON(src), false},
+		{"map key", []interface{}{"meta", "count"}, 2.0, false},
+		{"subtree", []interface{}{"meta", "tags"}, []interface{}{"a", "b"}, false},
+		{"slice element", []interface{}{"items", 1, "name"}, "y", false},
+		{"deep", []interface{}{"items", 0, "attrs", "deep", 1}, map[string]interface{}{"k": nil}, false},
+		{"null", []interface{}{"items", 0, "attrs", "deep", 1, "k"}, nil, false},
+		{"last", []interface{}{"last"}, true, false},
+
+		// Test errors:
+		{"missing key error", []interface{}{"meta", "x"}, nil, true},
+		{"index out of range error", []interface{}{"items", 2}, nil, true},
+		{"int path element error", []interface{}{"meta", 0}, nil, true},
+		{"string path element error", []interface{}{"items", "0"}, nil, true},
+		{"scalar node error", []interface{}{"last", "x"}, nil, true},
+	}
+
+	for _, c := range cases {
+		value, err := GetFromReader(strings.NewReader(src), c.path...)
+		if !reflect.DeepEqual(value, c.value) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+		// Errors must match those of Get:
+		if _, gerr := Get(decodeJSON(src), c.path...); c.isErr && gerr != nil && err != nil && gerr.Error() != err.Error() {
+			t.Errorf("[title: %s] Expected error: %v, got: %v", c.title, gerr, err)
+		}
+	}
+
+	// Invalid JSON:
+	if _, err := GetFromReader(strings.NewReader(`{"a": [1, }`), "b"); err == nil {
+		t.Errorf("Expected error for invalid JSON")
+	}
+	// Reading stops when the value is found, invalid data after it is not read:
+	if v, err := GetFromReader(strings.NewReader(`{"a": 1, "b": }`), "a"); v != 1.0 || err != nil {
+		t.Errorf("Expected value: %v, got: %v, err value: %v", 1.0, v, err)
+	}
+}
+
+func TestGetManyFromReader(t *testing.T) {
+	src := `{"a": {"b": [10, 20]}, "c": "x"}`
+	values, errs, err := GetManyFromReader(strings.NewReader(src),
+		[]interface{}{"c"},
+		[]interface{}{"a", "b", 1},
+		[]interface{}{"a"},
+		[]interface{}{"a", "x"},
+		[]interface{}{"a", "b", 0},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expValues := []interface{}{"x", 20.0, map[string]interface{}{"b": []interface{}{10.0, 20.0}}, nil, 10.0}
+	if !reflect.DeepEqual(values, expValues) {
+		t.Errorf("Expected values: %v, got: %v", expValues, values)
+	}
+	for i, err := range errs {
+		if isErr := i == 3; isErr != (err != nil) {
+			t.Errorf("[path: %d] Expected error: %v, got: %v, err value: %v", i, isErr, err != nil, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package dyno
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structFields describes the accessible fields of a struct type.
+type structFields struct {
+	names  []string         // Names of the fields in declaration order, shallower first
+	byName map[string][]int // Field index sequences by name (and by Go field name)
+}
+
+// structFieldsKey is the key of structFieldsCache.
+type structFieldsKey struct {
+	t    reflect.Type
+	tags string // Tag keys joined by commas
+}
+
+// structFieldsCache caches the structFields of struct types.
+var structFieldsCache sync.Map // map[structFieldsKey]*structFields
+
+// defaultTagKeys are the struct tag keys naming fields by default.
+var defaultTagKeys = []string{"json", "yaml"}
+
+// fieldsOf returns the fields of the struct type t named by the default
+// tag keys (see fieldsOfTags).
+func fieldsOf(t reflect.Type) *structFields {
+	return fieldsOfTags(t, defaultTagKeys)
+}
+
+// fieldsOfTags returns the fields of the struct type t.
+//
+// A field is named by its tag name of the first tag key in tags that has
+// one, or if there is none, by its Go name. Fields tagged with "-" and
+// unexported fields are excluded. Fields of embedded structs (and pointers
+// to structs) without a tag name are promoted; shallower fields take
+// precedence over deeper ones.
+func fieldsOfTags(t reflect.Type, tags []string) *structFields {
+	key := structFieldsKey{t: t, tags: strings.Join(tags, ",")}
+	if sf, ok := structFieldsCache.Load(key); ok {
+		return sf.(*structFields)
+	}
+
+	sf := &structFields{byName: map[string][]int{}}
+	aliases := map[string][]int{} // Go field names to register after all names
+
+	// Breadth-first traversal of embedded structs, so shallower fields win:
+	type level struct {
+		t     reflect.Type
+		index []int
+	}
+	levels := []level{{t: t}}
+	visited := map[reflect.Type]bool{}
+	for len(levels) > 0 {
+		var next []level
+		for _, l := range levels {
+			if visited[l.t] {
+				continue
+			}
+			visited[l.t] = true
+
+			for i := 0; i < l.t.NumField(); i++ {
+				f := l.t.Field(i)
+				index := append(append([]int(nil), l.index...), i)
+
+				name, tagged := fieldName(f, tags)
+				if name == "-" {
+					continue
+				}
+
+				if f.Anonymous && !tagged {
+					ft := f.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, level{t: ft, index: index})
+						continue
+					}
+				}
+				if f.PkgPath != "" { // Unexported
+					continue
+				}
+
+				if _, ok := sf.byName[name]; !ok {
+					sf.byName[name] = index
+					sf.names = append(sf.names, name)
+				}
+				if _, ok := aliases[f.Name]; !ok && f.Name != name {
+					aliases[f.Name] = index
+				}
+			}
+		}
+		levels = next
+	}
+
+	for name, index := range aliases {
+		if _, ok := sf.byName[name]; !ok {
+			sf.byName[name] = index
+		}
+	}
+
+	actual, _ := structFieldsCache.LoadOrStore(key, sf)
+	return actual.(*structFields)
+}
+
+// fieldName returns the name of the struct field f, and whether the name
+// comes from one of the given tag keys.
+func fieldName(f reflect.StructField, tags []string) (name string, tagged bool) {
+	for _, key := range tags {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		if tag == "-" {
+			return "-", true
+		}
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name, true
+		}
+	}
+	return f.Name, false
+}
+
+// structField returns the field of the struct rv denoted by the path
+// element el whose index in the path is i.
+//
+// exists is false if there is no such field, or if it is promoted through
+// a nil embedded pointer.
+func structField(rv reflect.Value, el interface{}, i int) (field reflect.Value, exists bool, err error) {
+	name, ok := el.(string)
+	if !ok {
+		return reflect.Value{}, false, fmt.Errorf("expected string path element, got: %T (path element idx: %d)", el, i)
+	}
+
+	index, ok := fieldsOf(rv.Type()).byName[name]
+	if !ok {
+		return reflect.Value{}, false, nil
+	}
+	field, exists = fieldByIndex(rv, index)
+	return field, exists, nil
+}
+
+// fieldByIndex returns the field of the struct rv denoted by the index
+// sequence. exists is false if the field is promoted through a nil embedded
+// pointer.
+func fieldByIndex(rv reflect.Value, index []int) (field reflect.Value, exists bool) {
+	for j, x := range index {
+		if j > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// structGetElem is the reflection fallback of getElem for structs.
+func structGetElem(rv reflect.Value, el interface{}, i int) (value interface{}, exists bool, err error) {
+	field, exists, err := structField(rv, el, i)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	return field.Interface(), true, nil
+}
+
+// structSetElem is the reflection fallback of setElem for structs.
+func structSetElem(rv reflect.Value, el interface{}, i int, value interface{}) error {
+	field, exists, err := structField(rv, el, i)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("missing field: %v (path element idx: %d)", el, i)
+	}
+	if !field.CanSet() {
+		return fmt.Errorf("cannot set field of unaddressable %v (path element idx: %d)", rv.Type(), i)
+	}
+	v, err := reflectValue(value, field.Type(), i)
+	if err != nil {
+		return err
+	}
+	field.Set(v)
+	return nil
+}
+
+// structChildren calls f for each accessible field of the struct rv, in
+// declaration order (promoted fields after the fields of rv).
+func structChildren(rv reflect.Value, f func(key, child interface{}) error) error {
+	for _, name := range fieldsOf(rv.Type()).names {
+		field, exists, _ := structField(rv, name, 0)
+		if !exists {
+			continue
+		}
+		if err := f(name, field.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
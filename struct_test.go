@@ -0,0 +1,194 @@
+package dyno
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testBase struct {
+	ID      int    `json:"id"`
+	Kind    string `yaml:"kind"`
+	private int
+}
+
+type testLabels struct {
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type testMeta struct {
+	testBase
+	*testLabels
+	Name    string                 `json:"name"`
+	Secret  string                 `json:"-"`
+	Plain   float64                // No tags
+	Extra   map[string]interface{} `yaml:"extra"`
+	Owner   *testBase              `json:"owner"`
+	Shadow  string                 `json:"id"` // Shallower than testBase.ID
+	private string
+}
+
+func TestStructGet(t *testing.T) {
+	defer enableReflect()()
+
+	meta := &testMeta{
+		testBase:   testBase{ID: 1, Kind: "k"},
+		testLabels: &testLabels{Labels: map[string]string{"app": "x"}},
+		Name:       "n",
+		Secret:     "s",
+		Plain:      1.5,
+		Extra:      map[string]interface{}{"list": []interface{}{"e"}},
+		Shadow:     "shadow",
+	}
+	v := map[string]interface{}{"meta": meta, "value": *meta}
+
+	cases := []struct {
+		title string        // Title of the test case
+		path  []interface{} // Input path
+		value interface{}   // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{"json tag", []interface{}{"meta", "name"}, "n", false},
+		{"go name", []interface{}{"meta", "Name"}, "n", false},
+		{"yaml tag", []interface{}{"meta", "extra", "list", 0}, "e", false},
+		{"no tags", []interface{}{"meta", "Plain"}, 1.5, false},
+		{"embedded", []interface{}{"meta", "kind"}, "k", false},
+		{"embedded pointer", []interface{}{"meta", "labels", "app"}, "x", false},
+		{"shallower field wins", []interface{}{"meta", "id"}, "shadow", false},
+		{"embedded by go name", []interface{}{"meta", "ID"}, 1, false},
+		{"struct value", []interface{}{"value", "name"}, "n", false},
+
+		// Test errors:
+		{"dash tag error", []interface{}{"meta", "Secret"}, nil, true},
+		{"unexported error", []interface{}{"meta", "private"}, nil, true},
+		{"missing field error", []interface{}{"meta", "x"}, nil, true},
+		{"nil pointer error", []interface{}{"meta", "owner", "id"}, nil, true},
+		{"int path element error", []interface{}{"meta", 0}, nil, true},
+	}
+
+	for _, c := range cases {
+		value, err := Get(v, c.path...)
+		if value != c.value {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	// Nil embedded pointer:
+	if _, err := Get(&testMeta{}, "labels"); err == nil {
+		t.Errorf("Expected error for field of nil embedded pointer")
+	}
+}
+
+func TestStructSet(t *testing.T) {
+	defer enableReflect()()
+
+	meta := &testMeta{Extra: map[string]interface{}{}}
+	v := map[string]interface{}{"meta": meta, "value": testMeta{}}
+
+	if err := Set(v, "n", "meta", "name"); err != nil || meta.Name != "n" {
+		t.Errorf("Expected name: %q, got: %q, err value: %v", "n", meta.Name, err)
+	}
+	if err := Set(v, 2, "meta", "ID"); err != nil || meta.ID != 2 {
+		t.Errorf("Expected id: %d, got: %d, err value: %v", 2, meta.ID, err)
+	}
+	if err := Set(v, &testBase{Kind: "o"}, "meta", "owner"); err != nil || meta.Owner == nil {
+		t.Errorf("Expected owner, got: %v, err value: %v", meta.Owner, err)
+	}
+	if err := Set(v, "k", "meta", "owner", "kind"); err != nil || meta.Owner.Kind != "k" {
+		t.Errorf("Expected owner kind: %q, got: %q, err value: %v", "k", meta.Owner.Kind, err)
+	}
+	if err := Set(v, 1, "meta", "extra", "x"); err != nil || meta.Extra["x"] != 1 {
+		t.Errorf("Expected extra: %v, got: %v, err value: %v", 1, meta.Extra["x"], err)
+	}
+
+	errCases := []struct {
+		title string        // Title of the test case
+		path  []interface{} // Input path
+		value interface{}   // Value to set
+	}{
+		{"unaddressable struct error", []interface{}{"value", "name"}, "x"},
+		{"non-assignable value error", []interface{}{"meta", "name"}, 1},
+		{"missing field error", []interface{}{"meta", "x"}, 1},
+		{"nil embedded pointer error", []interface{}{"meta", "labels"}, map[string]string{}},
+	}
+	for _, c := range errCases {
+		if err := Set(v, c.value, c.path...); err == nil {
+			t.Errorf("[title: %s] Expected error", c.title)
+		}
+	}
+}
+
+func TestStructWildcard(t *testing.T) {
+	defer enableReflect()()
+
+	v := []interface{}{
+		&testBase{ID: 1, Kind: "a"},
+		map[string]interface{}{"id": 2},
+	}
+
+	values, _ := GetAll(v, Any, "id")
+	if exp := []interface{}{1, 2}; !reflect.DeepEqual(values, exp) {
+		t.Errorf("Expected values: %v, got: %v", exp, values)
+	}
+	_, paths := GetAll(v, 0, Any)
+	if exp := [][]interface{}{{0, "id"}, {0, "kind"}}; !reflect.DeepEqual(paths, exp) {
+		t.Errorf("Expected paths: %v, got: %v", exp, paths)
+	}
+}
+
+func TestStructWithoutReflectFallback(t *testing.T) {
+	old := ReflectFallback
+	ReflectFallback = false
+	defer func() { ReflectFallback = old }()
+
+	meta := &testMeta{Name: "n", testLabels: &testLabels{Labels: map[string]string{"app": "x"}}}
+	v := map[string]interface{}{"metadata": meta}
+
+	if name, err := Get(v, "metadata", "name"); name != "n" || err != nil {
+		t.Errorf("Expected value: %v, got: %v, err value: %v", "n", name, err)
+	}
+	if err := Set(v, "m", "metadata", "name"); err != nil || meta.Name != "m" {
+		t.Errorf("Expected value: %v, got: %v, err value: %v", "m", meta.Name, err)
+	}
+	// Typed maps in structs still require ReflectFallback:
+	if _, err := Get(v, "metadata", "labels", "app"); err == nil {
+		t.Errorf("Expected error for typed map")
+	}
+}
+
+type testNode struct {
+	Name   string    `json:"name"`
+	Parent *testNode `json:"parent"`
+}
+
+func TestStructCycle(t *testing.T) {
+	for _, fallback := range []bool{false, true} {
+		old := ReflectFallback
+		ReflectFallback = fallback
+
+		n := &testNode{Name: "n"}
+		n.Parent = n
+		v := map[string]interface{}{"node": n}
+
+		// The struct being walked is not descended into again:
+		_, paths := GetAll(v, AnyDeep, "name")
+		expPaths := [][]interface{}{{"node", "name"}, {"node", "parent", "name"}}
+		if !reflect.DeepEqual(paths, expPaths) {
+			t.Errorf("[fallback: %v] Expected paths: %v, got: %v", fallback, expPaths, paths)
+		}
+
+		found := FindValue(v, func(value interface{}) bool { return value == "n" }, 0)
+		if exp := expPaths[:1]; !reflect.DeepEqual(found, exp) {
+			t.Errorf("[fallback: %v] Expected paths: %v, got: %v", fallback, exp, found)
+		}
+
+		if _, paths, err := SetAll(v, "m", "node", AnyDeep, "name"); err != nil || !reflect.DeepEqual(paths, expPaths) || n.Name != "m" {
+			t.Errorf("[fallback: %v] Expected paths: %v, got: %v, err value: %v", fallback, expPaths, paths, err)
+		}
+
+		ReflectFallback = old
+	}
+}
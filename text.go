@@ -0,0 +1,154 @@
+package dyno
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// TextOpts holds options for GetTextOpts.
+type TextOpts struct {
+	// JSON tells if maps and slices are to be rendered as JSON text.
+	// If false, maps and slices result in an error.
+	JSON bool
+}
+
+// GetText returns the value denoted by the path rendered as a string.
+//
+// Maps and slices are not accepted. See GetTextOpts for details.
+//
+// If path is empty or nil, v is returned as a string.
+func GetText(v interface{}, path ...interface{}) (string, error) {
+	return GetTextOpts(v, nil, path...)
+}
+
+// GetTextOpts returns the value denoted by the path rendered as a string.
+//
+// Unlike GetString, this function accepts many different types and renders them as text, namely:
+//   -string
+//   -[]byte (its content is returned as-is)
+//   -bool ("true" or "false")
+//   -integer types (int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64)
+//   -floating point types (float64, float32), using the shortest representation
+//    that round-trips, in decimal notation (e.g. 1000000 and not 1e+06) unless
+//    the exponent is very small or very large (same rules as encoding/json)
+//   -json.Number (its content is returned as-is)
+//   -any type implementing encoding.TextMarshaler
+//   -any type implementing fmt.Stringer
+//   -nil (the empty string is returned)
+//   -maps and slices if opts.JSON is true (rendered as JSON text, maps with
+//    interface{} keys are converted like ConvertMapI2MapS does, without
+//    modifying v)
+// Values of other types are rendered using fmt.Sprint().
+//
+// opts may be nil in which case the default options are used.
+//
+// If path is empty or nil, v is returned as a string.
+func GetTextOpts(v interface{}, opts *TextOpts, path ...interface{}) (string, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return "", err
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return x, nil
+	case []byte:
+		return string(x), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case int:
+		return strconv.FormatInt(int64(x), 10), nil
+	case int64:
+		return strconv.FormatInt(x, 10), nil
+	case int32:
+		return strconv.FormatInt(int64(x), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(x), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(x), 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(x), 10), nil
+	case uint64:
+		return strconv.FormatUint(x, 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(x), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(x), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(x), 10), nil
+	case float64:
+		return formatFloat(x, 64), nil
+	case float32:
+		return formatFloat(float64(x), 32), nil
+	case json.Number:
+		return string(x), nil
+	case encoding.TextMarshaler:
+		data, err := x.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case fmt.Stringer:
+		return x.String(), nil
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}, Container:
+		if opts == nil || !opts.JSON {
+			return "", fmt.Errorf("expected scalar value, got: %T", v)
+		}
+		data, err := json.Marshal(jsonCompatible(v))
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// formatFloat formats f using the shortest representation that round-trips.
+// Decimal notation is used unless the exponent is very small or very large,
+// the same way as encoding/json does.
+func formatFloat(f float64, bitSize int) string {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	return strconv.FormatFloat(f, format, -1, bitSize)
+}
+
+// jsonCompatible returns a copy of the dynamic object v where maps with
+// interface{} key type are converted to maps with string key type.
+// Unlike ConvertMapI2MapS, v is not modified.
+func jsonCompatible(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v2 := range x {
+			m[fmt.Sprint(k)] = jsonCompatible(v2)
+		}
+		return m
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v2 := range x {
+			m[k] = jsonCompatible(v2)
+		}
+		return m
+
+	case []interface{}:
+		s := make([]interface{}, len(x))
+		for i, v2 := range x {
+			s[i] = jsonCompatible(v2)
+		}
+		return s
+	}
+
+	return v
+}
@@ -0,0 +1,154 @@
+package dyno
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+type testStringer struct{}
+
+func (testStringer) String() string { return "stringer" }
+
+func TestGetTextOpts(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		opts  *TextOpts     // Options
+		path  []interface{} // path whose value to get
+		value string        // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from string",
+			v:     ms,
+			path:  []interface{}{"str"},
+			value: "abc",
+		},
+		{
+			title: "success from int",
+			v:     ms,
+			path:  []interface{}{"a"},
+			value: "1",
+		},
+		{
+			title: "success from uint64",
+			v:     uint64(math.MaxUint64),
+			value: "18446744073709551615",
+		},
+		{
+			title: "success from integral float64",
+			v:     1e6,
+			value: "1000000",
+		},
+		{
+			title: "success from float64",
+			v:     3.14,
+			value: "3.14",
+		},
+		{
+			title: "success from huge float64",
+			v:     1e21,
+			value: "1e+21",
+		},
+		{
+			title: "success from float32",
+			v:     float32(0.1),
+			value: "0.1",
+		},
+		{
+			title: "success from bool",
+			v:     true,
+			value: "true",
+		},
+		{
+			title: "success from json.Number",
+			v:     json.Number("12345678901234567890"),
+			value: "12345678901234567890",
+		},
+		{
+			title: "success from []byte",
+			v:     []byte("bytes"),
+			value: "bytes",
+		},
+		{
+			title: "success from TextMarshaler",
+			v:     net.IPv4(1, 2, 3, 4),
+			value: "1.2.3.4",
+		},
+		{
+			title: "success from time.Time (TextMarshaler)",
+			v:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			value: "2020-01-02T03:04:05Z",
+		},
+		{
+			title: "success from Stringer",
+			v:     testStringer{},
+			value: "stringer",
+		},
+		{
+			title: "success from nil",
+			v:     nil,
+			value: "",
+		},
+		{
+			title: "success from map with JSON option",
+			v:     map[interface{}]interface{}{"a": []interface{}{1, map[interface{}]interface{}{2: 3}}},
+			opts:  &TextOpts{JSON: true},
+			value: `{"a":[1,{"2":3}]}`,
+		},
+		{
+			title: "success from ordered map with JSON option",
+			v:     mustOrdered(`{"b":1,"a":[2]}`),
+			opts:  &TextOpts{JSON: true},
+			value: `{"b":1,"a":[2]}`,
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "expected scalar value error (slice)",
+			v:     ms,
+			path:  []interface{}{"s"},
+			isErr: true,
+		},
+		{
+			title: "expected scalar value error (ordered map)",
+			v:     mustOrdered(`{"a":1}`),
+			isErr: true,
+		},
+		{
+			title: "expected scalar value error (map)",
+			v:     ms,
+			opts:  &TextOpts{},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetTextOpts(c.v, c.opts, c.path...)
+		if value != c.value {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	// JSON rendering must not modify the source:
+	v := map[string]interface{}{"m": map[interface{}]interface{}{1: 2}}
+	if _, err := GetTextOpts(v, &TextOpts{JSON: true}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, ok := v["m"].(map[interface{}]interface{}); !ok {
+		t.Errorf("Source was modified: %v", v)
+	}
+}
@@ -0,0 +1,290 @@
+package dyno
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetDuration returns a time.Duration value denoted by the path.
+//
+// Numbers are interpreted as nanoseconds (which matches the underlying
+// representation of time.Duration). See GetDurationUnit for details.
+//
+// If path is empty or nil, v is returned as a time.Duration.
+func GetDuration(v interface{}, path ...interface{}) (time.Duration, error) {
+	return GetDurationUnit(v, time.Nanosecond, path...)
+}
+
+// GetDurationUnit returns a time.Duration value denoted by the path.
+//
+// This function accepts many different types and converts them to time.Duration, namely:
+//   -time.Duration
+//   -string (time.ParseDuration() will be used for parsing, e.g. "1h30m";
+//    strings holding a plain number are treated as numbers)
+//   -integer and floating point types, interpreted in the given unit
+//    (e.g. 30 with time.Second unit is 30 seconds)
+//   -any type with an Int64() (int64, error) or Float64() (float64, error)
+//    method (e.g. json.Number), interpreted in the given unit
+//
+// If path is empty or nil, v is returned as a time.Duration.
+func GetDurationUnit(v interface{}, unit time.Duration, path ...interface{}) (time.Duration, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return 0, err
+	}
+
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		if parsed, err := time.ParseDuration(d); err == nil {
+			return parsed, nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(d), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %q", d)
+		}
+		return floatToDuration(f, unit)
+	case float64:
+		return floatToDuration(d, unit)
+	case float32:
+		return floatToDuration(float64(d), unit)
+	case interface {
+		Int64() (int64, error)
+	}:
+		if n, err := d.Int64(); err == nil {
+			return intToDuration(n, unit)
+		}
+		if f, ok := d.(interface {
+			Float64() (float64, error)
+		}); ok {
+			n, err := f.Float64()
+			if err != nil {
+				return 0, err
+			}
+			return floatToDuration(n, unit)
+		}
+		return 0, fmt.Errorf("expected some form of duration, got: %T", v)
+	}
+
+	n, err := GetInteger(v)
+	if err != nil {
+		return 0, fmt.Errorf("expected some form of duration, got: %T", v)
+	}
+	return intToDuration(n, unit)
+}
+
+// intToDuration returns n units as a time.Duration, with overflow check.
+func intToDuration(n int64, unit time.Duration) (time.Duration, error) {
+	d := time.Duration(n) * unit
+	if unit != 0 && d/unit != time.Duration(n) {
+		return 0, fmt.Errorf("duration out of range: %d * %v", n, unit)
+	}
+	return d, nil
+}
+
+// floatToDuration returns f units as a time.Duration, with overflow check.
+func floatToDuration(f float64, unit time.Duration) (time.Duration, error) {
+	ns := f * float64(unit)
+	if math.IsNaN(ns) || ns >= math.MaxInt64 || ns < math.MinInt64 {
+		return 0, fmt.Errorf("duration out of range: %v * %v", f, unit)
+	}
+	return time.Duration(ns), nil
+}
+
+// TimeOpts holds options for GetTimeOpts.
+type TimeOpts struct {
+	// Layouts to try in order when parsing strings (see time.Parse()).
+	// If empty, time.RFC3339Nano is used (which also accepts time.RFC3339).
+	Layouts []string
+
+	// EpochUnit is the unit of numbers interpreted as Unix epoch time.
+	// If zero, time.Second is used. Use time.Millisecond for epoch millis.
+	EpochUnit time.Duration
+
+	// Location to use for layouts without zone information and for
+	// times created from epoch numbers. If nil, time.UTC is used.
+	Location *time.Location
+}
+
+// GetTime returns a time.Time value denoted by the path.
+//
+// Strings are parsed using the time.RFC3339 format, numbers are interpreted
+// as Unix epoch seconds. See GetTimeOpts for details.
+//
+// If path is empty or nil, v is returned as a time.Time.
+func GetTime(v interface{}, path ...interface{}) (time.Time, error) {
+	return GetTimeOpts(v, nil, path...)
+}
+
+// GetTimeOpts returns a time.Time value denoted by the path.
+//
+// This function accepts many different types and converts them to time.Time, namely:
+//   -time.Time
+//   -string (parsed using the layouts given in opts; strings holding
+//    a plain number are treated as numbers)
+//   -integer and floating point types, interpreted as Unix epoch time
+//    in the unit given in opts
+//   -any type with an Int64() (int64, error) or Float64() (float64, error)
+//    method (e.g. json.Number), interpreted as Unix epoch time
+//
+// opts may be nil in which case the default options are used.
+//
+// If path is empty or nil, v is returned as a time.Time.
+func GetTimeOpts(v interface{}, opts *TimeOpts, path ...interface{}) (time.Time, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if opts == nil {
+		opts = &TimeOpts{}
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		layouts := opts.Layouts
+		if len(layouts) == 0 {
+			layouts = []string{time.RFC3339Nano}
+		}
+		for _, layout := range layouts {
+			if parsed, err := time.ParseInLocation(layout, t, loc); err == nil {
+				return parsed, nil
+			}
+		}
+		s := strings.TrimSpace(t)
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return epochToTime(float64(n), n, true, opts.EpochUnit, loc), nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return epochToTime(f, 0, false, opts.EpochUnit, loc), nil
+		}
+		return time.Time{}, fmt.Errorf("invalid time: %q", t)
+	case float64:
+		return epochToTime(t, 0, false, opts.EpochUnit, loc), nil
+	case float32:
+		return epochToTime(float64(t), 0, false, opts.EpochUnit, loc), nil
+	case interface {
+		Int64() (int64, error)
+	}:
+		if n, err := t.Int64(); err == nil {
+			return epochToTime(float64(n), n, true, opts.EpochUnit, loc), nil
+		}
+		if f, ok := t.(interface {
+			Float64() (float64, error)
+		}); ok {
+			n, err := f.Float64()
+			if err != nil {
+				return time.Time{}, err
+			}
+			return epochToTime(n, 0, false, opts.EpochUnit, loc), nil
+		}
+		return time.Time{}, fmt.Errorf("expected some form of time, got: %T", v)
+	}
+
+	n, err := GetInteger(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected some form of time, got: %T", v)
+	}
+	return epochToTime(float64(n), n, true, opts.EpochUnit, loc), nil
+}
+
+// epochToTime converts a Unix epoch number given in unit to time.Time.
+// If isInt is true, n is used (preserving precision), else f is used.
+func epochToTime(f float64, n int64, isInt bool, unit time.Duration, loc *time.Location) time.Time {
+	if unit <= 0 {
+		unit = time.Second
+	}
+
+	var t time.Time
+	switch {
+	case isInt && time.Second%unit == 0:
+		per := int64(time.Second / unit)
+		t = time.Unix(n/per, n%per*int64(unit))
+	case isInt && unit%time.Second == 0:
+		t = time.Unix(n*int64(unit/time.Second), 0)
+	default:
+		if isInt {
+			f = float64(n)
+		}
+		secs := f * float64(unit) / float64(time.Second)
+		whole := math.Floor(secs)
+		t = time.Unix(int64(whole), int64((secs-whole)*float64(time.Second)))
+	}
+
+	return t.In(loc)
+}
+
+// byteSizeUnits maps lowercased byte size suffixes to their multipliers.
+var byteSizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "ki": 1 << 10, "kib": 1 << 10,
+	"m": 1e6, "mb": 1e6, "mi": 1 << 20, "mib": 1 << 20,
+	"g": 1e9, "gb": 1e9, "gi": 1 << 30, "gib": 1 << 30,
+	"t": 1e12, "tb": 1e12, "ti": 1 << 40, "tib": 1 << 40,
+	"p": 1e15, "pb": 1e15, "pi": 1 << 50, "pib": 1 << 50,
+	"e": 1e18, "eb": 1e18, "ei": 1 << 60, "eib": 1 << 60,
+}
+
+// GetByteSize returns a byte size denoted by the path as an int64.
+//
+// This function accepts many different types and converts them to int64, namely:
+//   -string with an optional unit suffix, e.g. "512MiB", "1.5 GB", "100k".
+//    Both SI (KB, MB, GB, TB, PB, EB; powers of 1000) and IEC (KiB, MiB,
+//    GiB, TiB, PiB, EiB; powers of 1024) suffixes are supported, the "B"
+//    and "i" may be omitted, and suffixes are case insensitive.
+//   -all other types accepted by GetInteger, interpreted as number of bytes
+//
+// If path is empty or nil, v is returned as a byte size.
+func GetByteSize(v interface{}, path ...interface{}) (int64, error) {
+	v, err := Get(v, path...)
+	if err != nil {
+		return 0, err
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return GetInteger(v)
+	}
+
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.' || r == '+' || r == '-')
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	num, suffix := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	mult, ok := byteSizeUnits[suffix]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size unit: %q", s[i:])
+	}
+
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		size := n * mult
+		if n != 0 && size/n != mult {
+			return 0, fmt.Errorf("byte size out of range: %q", s)
+		}
+		return size, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size: %q", s)
+	}
+	f *= float64(mult)
+	if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("byte size out of range: %q", s)
+	}
+	return int64(f), nil
+}
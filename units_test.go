@@ -0,0 +1,286 @@
+package dyno
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGetDurationUnit(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		unit  time.Duration // Unit of numbers
+		path  []interface{} // path whose value to get
+		value time.Duration // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from time.Duration",
+			v:     map[string]interface{}{"a": 3 * time.Second},
+			path:  []interface{}{"a"},
+			unit:  time.Millisecond,
+			value: 3 * time.Second,
+		},
+		{
+			title: "success from string",
+			v:     map[string]interface{}{"a": "1m30s"},
+			path:  []interface{}{"a"},
+			unit:  time.Second,
+			value: 90 * time.Second,
+		},
+		{
+			title: "success from numeric string",
+			v:     "30",
+			unit:  time.Second,
+			value: 30 * time.Second,
+		},
+		{
+			title: "success from int",
+			v:     30,
+			unit:  time.Second,
+			value: 30 * time.Second,
+		},
+		{
+			title: "success from uint16",
+			v:     uint16(5),
+			unit:  time.Millisecond,
+			value: 5 * time.Millisecond,
+		},
+		{
+			title: "success from float64",
+			v:     1.5,
+			unit:  time.Second,
+			value: 1500 * time.Millisecond,
+		},
+		{
+			title: "success from json.Number",
+			v:     json.Number("2"),
+			unit:  time.Minute,
+			value: 2 * time.Minute,
+		},
+		{
+			title: "success from fractional json.Number",
+			v:     json.Number("0.5"),
+			unit:  time.Second,
+			value: 500 * time.Millisecond,
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			unit:  time.Second,
+			isErr: true,
+		},
+		{
+			title: "invalid duration string error",
+			v:     "1 fortnight",
+			unit:  time.Second,
+			isErr: true,
+		},
+		{
+			title: "overflow error",
+			v:     int64(1) << 62,
+			unit:  time.Hour,
+			isErr: true,
+		},
+		{
+			title: "expected some form of duration error",
+			v:     []interface{}{},
+			unit:  time.Second,
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetDurationUnit(c.v, c.unit, c.path...)
+		if value != c.value {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+
+	// GetDuration uses nanoseconds:
+	if d, err := GetDuration(int64(1000)); d != time.Microsecond || err != nil {
+		t.Errorf("Expected value: %v, got: %v, err value: %v", time.Microsecond, d, err)
+	}
+}
+
+func TestGetTimeOpts(t *testing.T) {
+	ref := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		opts  *TimeOpts     // Options
+		path  []interface{} // path whose value to get
+		value time.Time     // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from time.Time",
+			v:     map[string]interface{}{"t": ref},
+			path:  []interface{}{"t"},
+			value: ref,
+		},
+		{
+			title: "success from RFC 3339 string",
+			v:     map[string]interface{}{"t": "2020-01-02T03:04:05Z"},
+			path:  []interface{}{"t"},
+			value: ref,
+		},
+		{
+			title: "success from custom layout",
+			v:     "2020-01-02 03:04:05",
+			opts:  &TimeOpts{Layouts: []string{time.RFC3339, "2006-01-02 15:04:05"}},
+			value: ref,
+		},
+		{
+			title: "success from epoch seconds",
+			v:     ref.Unix(),
+			value: ref,
+		},
+		{
+			title: "success from epoch seconds string",
+			v:     "1577934245",
+			value: ref,
+		},
+		{
+			title: "success from epoch millis",
+			v:     float64(ref.Unix()*1000 + 500),
+			opts:  &TimeOpts{EpochUnit: time.Millisecond},
+			value: ref.Add(500 * time.Millisecond),
+		},
+		{
+			title: "success from epoch millis json.Number",
+			v:     json.Number("1577934245250"),
+			opts:  &TimeOpts{EpochUnit: time.Millisecond},
+			value: ref.Add(250 * time.Millisecond),
+		},
+		{
+			title: "success from fractional epoch seconds",
+			v:     1577934245.5,
+			value: ref.Add(500 * time.Millisecond),
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid time string error",
+			v:     "yesterday",
+			isErr: true,
+		},
+		{
+			title: "expected some form of time error",
+			v:     true,
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetTimeOpts(c.v, c.opts, c.path...)
+		if !value.Equal(c.value) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestGetByteSize(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to get
+		value int64         // Expected value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "success from int",
+			v:     map[string]interface{}{"size": 100},
+			path:  []interface{}{"size"},
+			value: 100,
+		},
+		{
+			title: "success from plain string",
+			v:     "100",
+			value: 100,
+		},
+		{
+			title: "success from IEC suffix",
+			v:     "512MiB",
+			value: 512 << 20,
+		},
+		{
+			title: "success from SI suffix",
+			v:     "2 GB",
+			value: 2e9,
+		},
+		{
+			title: "success from short lowercase suffix",
+			v:     "4k",
+			value: 4000,
+		},
+		{
+			title: "success from fractional value",
+			v:     "1.5KiB",
+			value: 1536,
+		},
+		{
+			title: "success from bytes suffix",
+			v:     "10B",
+			value: 10,
+		},
+
+		// Test errors:
+		{
+			title: "internal Get call returns error",
+			v:     ms,
+			path:  []interface{}{"x"},
+			isErr: true,
+		},
+		{
+			title: "invalid unit error",
+			v:     "12XB",
+			isErr: true,
+		},
+		{
+			title: "invalid number error",
+			v:     "1.2.3MB",
+			isErr: true,
+		},
+		{
+			title: "overflow error",
+			v:     "100EiB",
+			isErr: true,
+		},
+		{
+			title: "expected some form of integer error",
+			v:     []interface{}{},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		value, err := GetByteSize(c.v, c.path...)
+		if value != c.value {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.value, value)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package dyno
+
+// Update applies fn to the value denoted by the path, and sets the value
+// returned by fn.
+//
+// fn receives the current value and whether it exists (a map key that does
+// not exist is not an error, fn is called with exists=false). If fn returns
+// an error, the value is left unchanged and the error is returned.
+//
+// The map or slice holding the value must already exist. It is resolved
+// only once.
+//
+// Path cannot be empty or nil, else an error is returned.
+func Update(v interface{}, fn func(old interface{}, exists bool) (interface{}, error), path ...interface{}) error {
+	node, err := getParent(v, path)
+	if err != nil {
+		return err
+	}
+
+	i := len(path) - 1 // The last index
+	old, exists, err := getElem(node, path[i], i)
+	if err != nil {
+		return err
+	}
+
+	value, err := fn(old, exists)
+	if err != nil {
+		return err
+	}
+	return setElem(node, path[i], i, value)
+}
+
+// SetIfAbsent sets a map element denoted by the path only if it does not
+// exist yet. The return value tells if the value was set.
+//
+// Slice elements always exist, so if the path denotes a slice element,
+// nothing is set (and an error is returned if the index is invalid).
+//
+// Path cannot be empty or nil, else an error is returned.
+func SetIfAbsent(v interface{}, value interface{}, path ...interface{}) (bool, error) {
+	node, err := getParent(v, path)
+	if err != nil {
+		return false, err
+	}
+
+	i := len(path) - 1 // The last index
+	_, exists, err := getElem(node, path[i], i)
+	if err != nil || exists {
+		return false, err
+	}
+	return true, setElem(node, path[i], i, value)
+}
+
+// SetIfEqual sets a map or slice element denoted by the path only if its
+// current value equals to expected (as defined by Equal). The return value
+// tells if the value was set.
+//
+// If the element does not exist, nothing is set.
+//
+// Path cannot be empty or nil, else an error is returned.
+func SetIfEqual(v interface{}, expected, value interface{}, path ...interface{}) (bool, error) {
+	node, err := getParent(v, path)
+	if err != nil {
+		return false, err
+	}
+
+	i := len(path) - 1 // The last index
+	old, exists, err := getElem(node, path[i], i)
+	if err != nil || !exists || !Equal(old, expected) {
+		return false, err
+	}
+	return true, setElem(node, path[i], i, value)
+}
+
+// GetOrSet returns the value denoted by the path if it exists, else sets
+// value and returns it.
+//
+// The map or slice holding the value must already exist.
+//
+// Path cannot be empty or nil, else an error is returned.
+func GetOrSet(v interface{}, value interface{}, path ...interface{}) (interface{}, error) {
+	node, err := getParent(v, path)
+	if err != nil {
+		return nil, err
+	}
+
+	i := len(path) - 1 // The last index
+	old, exists, err := getElem(node, path[i], i)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return old, nil
+	}
+	if err := setElem(node, path[i], i, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
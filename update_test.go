@@ -0,0 +1,278 @@
+package dyno
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	appendX := func(old interface{}, exists bool) (interface{}, error) {
+		if !exists {
+			return "x", nil
+		}
+		s, ok := old.(string)
+		if !ok {
+			return nil, errors.New("not a string")
+		}
+		return s + "x", nil
+	}
+
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		path  []interface{} // path whose value to update
+		exp   interface{}   // Expected result
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "update existing map element",
+			v:     map[string]interface{}{"a": "a"},
+			path:  []interface{}{"a"},
+			exp:   map[string]interface{}{"a": "ax"},
+		},
+		{
+			title: "update missing map element",
+			v:     map[interface{}]interface{}{},
+			path:  []interface{}{1},
+			exp:   map[interface{}]interface{}{1: "x"},
+		},
+		{
+			title: "update nested slice element",
+			v:     map[string]interface{}{"s": []interface{}{"a", "b"}},
+			path:  []interface{}{"s", 1},
+			exp:   map[string]interface{}{"s": []interface{}{"a", "bx"}},
+		},
+
+		// Test errors:
+		{
+			title: "path cannot be empty error",
+			v:     "a",
+			exp:   "a",
+			isErr: true,
+		},
+		{
+			title: "fn returns error",
+			v:     map[string]interface{}{"a": 1},
+			path:  []interface{}{"a"},
+			exp:   map[string]interface{}{"a": 1},
+			isErr: true,
+		},
+		{
+			title: "index out of range error",
+			v:     []interface{}{"a"},
+			path:  []interface{}{1},
+			exp:   []interface{}{"a"},
+			isErr: true,
+		},
+		{
+			title: "internal Get call returns error",
+			v:     map[string]interface{}{},
+			path:  []interface{}{"x", "y"},
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		err := Update(c.v, appendX, c.path...)
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestSetIfAbsent(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		value interface{}   // Value to set
+		path  []interface{} // path whose value to set
+		exp   interface{}   // Expected result
+		set   bool          // Expected set result
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "absent key",
+			v:     map[string]interface{}{"a": 1},
+			value: 2,
+			path:  []interface{}{"b"},
+			exp:   map[string]interface{}{"a": 1, "b": 2},
+			set:   true,
+		},
+		{
+			title: "existing key",
+			v:     map[string]interface{}{"a": 1},
+			value: 2,
+			path:  []interface{}{"a"},
+			exp:   map[string]interface{}{"a": 1},
+		},
+		{
+			title: "existing key with nil value",
+			v:     map[string]interface{}{"a": nil},
+			value: 2,
+			path:  []interface{}{"a"},
+			exp:   map[string]interface{}{"a": nil},
+		},
+		{
+			title: "slice element",
+			v:     []interface{}{1},
+			value: 2,
+			path:  []interface{}{0},
+			exp:   []interface{}{1},
+		},
+
+		// Test errors:
+		{
+			title: "expected string path element error",
+			v:     map[string]interface{}{},
+			value: 2,
+			path:  []interface{}{1},
+			exp:   map[string]interface{}{},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		set, err := SetIfAbsent(c.v, c.value, c.path...)
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if set != c.set {
+			t.Errorf("[title: %s] Expected set: %v, got: %v", c.title, c.set, set)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestSetIfEqual(t *testing.T) {
+	cases := []struct {
+		title    string        // Title of the test case
+		v        interface{}   // Input dynamic object
+		expected interface{}   // Expected current value
+		value    interface{}   // Value to set
+		path     []interface{} // path whose value to set
+		exp      interface{}   // Expected result
+		set      bool          // Expected set result
+		isErr    bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title:    "equal value",
+			v:        map[string]interface{}{"a": 1},
+			expected: 1.0,
+			value:    2,
+			path:     []interface{}{"a"},
+			exp:      map[string]interface{}{"a": 2},
+			set:      true,
+		},
+		{
+			title:    "structurally equal value",
+			v:        []interface{}{map[string]interface{}{"x": []interface{}{1}}},
+			expected: map[interface{}]interface{}{"x": []interface{}{1}},
+			value:    "new",
+			path:     []interface{}{0},
+			exp:      []interface{}{"new"},
+			set:      true,
+		},
+		{
+			title:    "different value",
+			v:        map[string]interface{}{"a": 1},
+			expected: 3,
+			value:    2,
+			path:     []interface{}{"a"},
+			exp:      map[string]interface{}{"a": 1},
+		},
+		{
+			title:    "missing key",
+			v:        map[string]interface{}{},
+			expected: nil,
+			value:    2,
+			path:     []interface{}{"a"},
+			exp:      map[string]interface{}{},
+		},
+
+		// Test errors:
+		{
+			title:    "path cannot be empty error",
+			v:        1,
+			expected: 1,
+			value:    2,
+			exp:      1,
+			isErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		set, err := SetIfEqual(c.v, c.expected, c.value, c.path...)
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if set != c.set {
+			t.Errorf("[title: %s] Expected set: %v, got: %v", c.title, c.set, set)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
+
+func TestGetOrSet(t *testing.T) {
+	cases := []struct {
+		title string        // Title of the test case
+		v     interface{}   // Input dynamic object
+		value interface{}   // Value to set
+		path  []interface{} // path whose value to get or set
+		exp   interface{}   // Expected result
+		res   interface{}   // Expected returned value
+		isErr bool          // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "existing key",
+			v:     map[string]interface{}{"a": 1},
+			value: 2,
+			path:  []interface{}{"a"},
+			exp:   map[string]interface{}{"a": 1},
+			res:   1,
+		},
+		{
+			title: "missing key",
+			v:     map[string]interface{}{"a": 1},
+			value: 2,
+			path:  []interface{}{"b"},
+			exp:   map[string]interface{}{"a": 1, "b": 2},
+			res:   2,
+		},
+
+		// Test errors:
+		{
+			title: "expected map or slice node error",
+			v:     map[string]interface{}{"a": 1},
+			value: 2,
+			path:  []interface{}{"a", "b"},
+			exp:   map[string]interface{}{"a": 1},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		res, err := GetOrSet(c.v, c.value, c.path...)
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if !reflect.DeepEqual(res, c.res) {
+			t.Errorf("[title: %s] Expected result: %v, got: %v", c.title, c.res, res)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package dyno
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UpdateError is the error returned by ApplyUpdate, it tells which
+// operator failed on which field.
+type UpdateError struct {
+	Op    string // Operator, e.g. "$inc"
+	Field string // Field path the operator was applied to (may be empty)
+	Err   error  // The underlying error
+}
+
+// Error returns the error message.
+func (e *UpdateError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *UpdateError) Unwrap() error {
+	return e.Err
+}
+
+// updateOps lists the supported update operators in the order they are applied.
+var updateOps = []string{
+	"$rename", "$set", "$unset", "$inc", "$mul", "$min", "$max", "$push", "$addToSet", "$pull",
+}
+
+// ApplyUpdate applies the MongoDB-style update document to v.
+//
+// update is a dynamic object whose keys are update operators and values
+// are maps from field paths to arguments, e.g.
+//
+//	{"$set": {"a.b": 1}, "$inc": {"n": 2}, "$push": {"tags": "x"}, "$unset": {"old": ""}}
+//
+// Field paths are resolved the same way as in Match (dot separated map keys
+// and slice indices).
+//
+// Supported operators:
+//   -$set: sets the field (missing intermediate maps are created)
+//   -$unset: deletes the field from its map (slice elements are set to nil)
+//   -$inc, $mul, $min, $max: see Increment, Multiply, Min and Max
+//   -$push: appends the value to the slice field, or all elements of the
+//    slice given as {"$each": [...]}; a missing field is created
+//   -$addToSet: like $push, but only appends values not yet in the slice
+//    (as defined by Equal)
+//   -$pull: removes all elements of the slice field equal to the argument,
+//    or matching it if it is a filter or an operator expression (see Match)
+//   -$rename: moves the field to the field path given as argument
+//
+// Operators are applied in the order listed above, fields of an operator in
+// sorted order.
+//
+// The update is atomic: either all operators succeed, or v is left
+// unchanged and an *UpdateError describing the first failing operator is
+// returned.
+func ApplyUpdate(v interface{}, update interface{}) error {
+	u, err := filterMap(update)
+	if err != nil {
+		return &UpdateError{Err: err}
+	}
+	for op := range u {
+		if !isUpdateOp(op) {
+			return &UpdateError{Op: op, Err: fmt.Errorf("unknown operator")}
+		}
+	}
+
+	// Changes are recorded, so they can be rolled back if an operator fails:
+	var log undoLog
+	if err := applyUpdate(v, u, &log); err != nil {
+		log.rollback()
+		return err
+	}
+	return nil
+}
+
+// undoEntry records the state of an element before an update operator
+// changed it.
+type undoEntry struct {
+	node   interface{} // Parent node of the element
+	el     interface{} // Path element denoting the element in node
+	idx    int         // Index of el in its path
+	value  interface{} // Previous value of the element
+	exists bool        // Tells if the element existed
+	pos    int         // Position of the key if node is an *OrderedMap and the element existed
+}
+
+// undoLog is a list of changes made by ApplyUpdate, in the order they were
+// made.
+type undoLog []undoEntry
+
+// record records the state of the element denoted by path in v before it is
+// changed. If an ancestor of the element is missing, the state of the first
+// missing ancestor is recorded instead.
+func (u *undoLog) record(v interface{}, path []interface{}) {
+	node := v
+	for i, el := range path {
+		child, exists, err := getElem(node, el, i)
+		if err != nil {
+			return // The operator will fail before changing anything
+		}
+		if !exists || i == len(path)-1 {
+			e := undoEntry{node: node, el: el, idx: i, value: child, exists: exists}
+			if m, ok := node.(*OrderedMap); ok && exists {
+				e.pos = m.index(el.(string))
+			}
+			*u = append(*u, e)
+			return
+		}
+		node = child
+	}
+}
+
+// rollback restores the recorded states in reverse order.
+func (u undoLog) rollback() {
+	for i := len(u) - 1; i >= 0; i-- {
+		e := u[i]
+		if e.exists {
+			setElem(e.node, e.el, e.idx, e.value)
+			// A deleted key would be restored at the end:
+			if m, ok := e.node.(*OrderedMap); ok {
+				m.move(e.el.(string), e.pos)
+			}
+		} else {
+			Delete(e.node, e.el)
+		}
+	}
+}
+
+// isUpdateOp tells if op is a supported update operator.
+func isUpdateOp(op string) bool {
+	for _, o := range updateOps {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// applyUpdate applies the operators of the update document u to v,
+// recording changes in log.
+func applyUpdate(v interface{}, u map[string]interface{}, log *undoLog) error {
+	for _, op := range updateOps {
+		arg, ok := u[op]
+		if !ok {
+			continue
+		}
+		fields, err := filterMap(arg)
+		if err != nil {
+			return &UpdateError{Op: op, Err: err}
+		}
+		for _, field := range sortedKeysS(fields) {
+			if err := applyUpdateOp(v, op, field, fields[field], log); err != nil {
+				return &UpdateError{Op: op, Field: field, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// applyUpdateOp applies a single operator to a single field, recording
+// changes in log.
+func applyUpdateOp(v interface{}, op, field string, arg interface{}, log *undoLog) error {
+	switch op {
+	case "$unset":
+		path, err := fieldPath(v, field, false, nil)
+		if err != nil {
+			return nil // Unsetting a missing field is a no-op
+		}
+		log.record(v, path)
+		i := len(path) - 1
+		if _, ok := path[i].(int); ok {
+			return Set(v, nil, path...)
+		}
+		return Delete(v, path[i], path[:i]...)
+
+	case "$rename":
+		to, ok := arg.(string)
+		if !ok {
+			return fmt.Errorf("expected string argument, got: %T", arg)
+		}
+		path, err := fieldPath(v, field, false, nil)
+		if err != nil {
+			return nil // Renaming a missing field is a no-op
+		}
+		log.record(v, path)
+		value, err := Get(v, path...)
+		if err != nil {
+			return err
+		}
+		i := len(path) - 1
+		if err := Delete(v, path[i], path[:i]...); err != nil {
+			return err
+		}
+		toPath, err := fieldPath(v, to, true, log)
+		if err != nil {
+			return err
+		}
+		log.record(v, toPath)
+		return Set(v, value, toPath...)
+	}
+
+	path, err := fieldPath(v, field, true, log)
+	if err != nil {
+		return err
+	}
+	log.record(v, path)
+
+	switch op {
+	case "$set":
+		return Set(v, arg, path...)
+	case "$inc":
+		return Increment(v, arg, path...)
+	case "$mul":
+		return Multiply(v, arg, path...)
+	case "$min":
+		return Min(v, arg, path...)
+	case "$max":
+		return Max(v, arg, path...)
+	}
+
+	// Remaining operators work on slices:
+	node, err := getParent(v, path)
+	if err != nil {
+		return err
+	}
+	cur, exists, err := getElem(node, path[len(path)-1], len(path)-1)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if op == "$pull" {
+			return nil
+		}
+		cur = []interface{}{}
+	}
+	s, ok := cur.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected slice node, got: %T", cur)
+	}
+
+	switch op {
+	case "$push":
+		return Set(v, append(s, eachValues(arg)...), path...)
+
+	case "$addToSet":
+	outer:
+		for _, value := range eachValues(arg) {
+			for _, el := range s {
+				if Equal(el, value) {
+					continue outer
+				}
+			}
+			s = append(s, value)
+		}
+		return Set(v, s, path...)
+
+	default: // "$pull"
+		_, isExpr := isOperatorExpr(arg)
+		_, ferr := filterMap(arg)
+		isFilter := ferr == nil
+		// A new slice is built, so the recorded previous value stays intact:
+		res := make([]interface{}, 0, len(s))
+		for _, el := range s {
+			var match bool
+			switch {
+			case isExpr:
+				match, err = matchCond(el, true, arg)
+			case isFilter:
+				match, err = Match(el, arg)
+			default:
+				match = Equal(el, arg)
+			}
+			if err != nil {
+				return err
+			}
+			if !match {
+				res = append(res, el)
+			}
+		}
+		return Set(v, res, path...)
+	}
+}
+
+// eachValues returns the values to add by $push and $addToSet:
+// the elements of the $each modifier if present, else arg itself.
+func eachValues(arg interface{}) []interface{} {
+	if m, err := filterMap(arg); err == nil && len(m) == 1 {
+		if each, ok := m["$each"].([]interface{}); ok {
+			return each
+		}
+	}
+	return []interface{}{arg}
+}
+
+// fieldPath converts the dotted field path to a dyno path, resolving
+// numeric path elements to slice indices (or int map keys) where needed.
+//
+// If create is true, missing intermediate maps are created (having the
+// same key type as their parent map) and recorded in log (if not nil), else
+// missing elements are errors.
+func fieldPath(v interface{}, field string, create bool, log *undoLog) ([]interface{}, error) {
+	segs := strings.Split(field, ".")
+	path := make([]interface{}, len(segs))
+
+	node := v
+	for i, seg := range segs {
+		var el interface{} = seg
+		switch n := node.(type) {
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil {
+				return nil, fmt.Errorf("expected int path element, got: %q (path element idx: %d)", seg, i)
+			}
+			el = idx
+		case map[interface{}]interface{}:
+			if _, ok := n[seg]; !ok {
+				if idx, err := strconv.Atoi(seg); err == nil {
+					if _, ok := n[idx]; ok {
+						el = idx
+					}
+				}
+			}
+		}
+		path[i] = el
+
+		if i == len(segs)-1 {
+			break
+		}
+
+		child, exists, err := getElem(node, el, i)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if !create {
+				return nil, fmt.Errorf("missing key: %v (path element idx: %d)", el, i)
+			}
+			if _, ok := node.(map[interface{}]interface{}); ok {
+				child = map[interface{}]interface{}{}
+			} else {
+				child = map[string]interface{}{}
+			}
+			if log != nil {
+				*log = append(*log, undoEntry{node: node, el: el, idx: i})
+			}
+			if err := setElem(node, el, i, child); err != nil {
+				return nil, err
+			}
+		}
+		node = child
+	}
+
+	if !create {
+		if _, exists, err := getElem(node, path[len(path)-1], len(path)-1); err != nil || !exists {
+			return nil, fmt.Errorf("missing key: %v (path element idx: %d)", path[len(path)-1], len(path)-1)
+		}
+	}
+
+	return path, nil
+}
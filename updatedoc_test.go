@@ -0,0 +1,171 @@
+package dyno
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyUpdate(t *testing.T) {
+	cases := []struct {
+		title  string // Title of the test case
+		v      string // Input dynamic object in JSON
+		update string // Update document in JSON
+		exp    string // Expected result in JSON
+		errOp  string // Operator of the expected error (empty if no error is expected)
+	}{
+		// Test success:
+		{
+			title:  "$set with nested path",
+			v:      `{"a": {"b": 0}}`,
+			update: `{"$set": {"a.b": 1, "a.c.d": 2}}`,
+			exp:    `{"a": {"b": 1, "c": {"d": 2}}}`,
+		},
+		{
+			title:  "$set slice element",
+			v:      `{"a": [1, 2]}`,
+			update: `{"$set": {"a.1": 3}}`,
+			exp:    `{"a": [1, 3]}`,
+		},
+		{
+			title:  "$unset",
+			v:      `{"a": 1, "old": 2, "s": [1, 2]}`,
+			update: `{"$unset": {"old": "", "missing.x": "", "s.0": ""}}`,
+			exp:    `{"a": 1, "s": [null, 2]}`,
+		},
+		{
+			title:  "$inc and $mul",
+			v:      `{"n": 1, "m": 3}`,
+			update: `{"$inc": {"n": 2, "new": 5}, "$mul": {"m": 2}}`,
+			exp:    `{"n": 3, "m": 6, "new": 5}`,
+		},
+		{
+			title:  "$min and $max",
+			v:      `{"lo": 5, "hi": 5}`,
+			update: `{"$min": {"lo": 3}, "$max": {"hi": 3}}`,
+			exp:    `{"lo": 3, "hi": 5}`,
+		},
+		{
+			title:  "$push",
+			v:      `{"tags": ["a"]}`,
+			update: `{"$push": {"tags": "x", "new": {"$each": [1, 2]}}}`,
+			exp:    `{"tags": ["a", "x"], "new": [1, 2]}`,
+		},
+		{
+			title:  "$addToSet",
+			v:      `{"tags": ["a", "b"]}`,
+			update: `{"$addToSet": {"tags": {"$each": ["b", "c", "c"]}}}`,
+			exp:    `{"tags": ["a", "b", "c"]}`,
+		},
+		{
+			title:  "$pull",
+			v:      `{"a": [1, 2, 1, 3], "b": [1, 5, 9], "c": [{"x": 1}, {"x": 2}]}`,
+			update: `{"$pull": {"a": 1, "b": {"$gte": 5}, "c": {"x": 2}, "missing": 1}}`,
+			exp:    `{"a": [2, 3], "b": [1], "c": [{"x": 1}]}`,
+		},
+		{
+			title:  "$rename",
+			v:      `{"a": {"b": 1}}`,
+			update: `{"$rename": {"a.b": "c.d", "missing": "x"}}`,
+			exp:    `{"a": {}, "c": {"d": 1}}`,
+		},
+
+		// Test errors:
+		{
+			title:  "unknown operator error",
+			v:      `{"a": 1}`,
+			update: `{"$set": {"a": 2}, "$foo": {"a": 1}}`,
+			exp:    `{"a": 1}`,
+			errOp:  "$foo",
+		},
+		{
+			title:  "atomic on failure",
+			v:      `{"a": 1, "s": "x"}`,
+			update: `{"$set": {"a": 2}, "$push": {"s": 1}}`,
+			exp:    `{"a": 1, "s": "x"}`,
+			errOp:  "$push",
+		},
+		{
+			title:  "rollback of created and renamed fields",
+			v:      `{"a": {"b": 1}, "l": [1, 2], "s": "x"}`,
+			update: `{"$rename": {"a.b": "c.d"}, "$set": {"x.y.z": 1}, "$pull": {"l": 1}, "$addToSet": {"s": 1}}`,
+			exp:    `{"a": {"b": 1}, "l": [1, 2], "s": "x"}`,
+			errOp:  "$addToSet",
+		},
+		{
+			title:  "$inc on non-number error",
+			v:      `{"a": "x"}`,
+			update: `{"$inc": {"a": 1}}`,
+			exp:    `{"a": "x"}`,
+			errOp:  "$inc",
+		},
+		{
+			title:  "invalid field path error",
+			v:      `{"a": [1]}`,
+			update: `{"$set": {"a.x": 1}}`,
+			exp:    `{"a": [1]}`,
+			errOp:  "$set",
+		},
+		{
+			title:  "non-map operator argument error",
+			v:      `{"a": 1}`,
+			update: `{"$set": 1}`,
+			exp:    `{"a": 1}`,
+			errOp:  "$set",
+		},
+	}
+
+	for _, c := range cases {
+		v := decodeJSON(c.v)
+		err := ApplyUpdate(v, decodeJSON(c.update))
+		if !Equal(v, decodeJSON(c.exp)) {
+			got, _ := json.Marshal(v)
+			t.Errorf("[title: %s] Expected value: %s, got: %s", c.title, c.exp, got)
+		}
+		var uerr *UpdateError
+		if c.errOp == "" && err != nil || c.errOp != "" && (!errors.As(err, &uerr) || uerr.Op != c.errOp) {
+			t.Errorf("[title: %s] Expected error op: %q, got err value: %v", c.title, c.errOp, err)
+		}
+	}
+}
+
+func TestApplyUpdateTyped(t *testing.T) {
+	defer enableReflect()()
+
+	type counter struct{ N int }
+	counts := map[string]int{"a": 1}
+	c := &counter{N: 1}
+	v := map[string]interface{}{"counts": counts, "c": c}
+
+	if err := ApplyUpdate(v, decodeJSON(`{"$inc": {"counts.a": 1, "c.N": 1}}`)); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if counts["a"] != 2 || c.N != 2 {
+		t.Errorf("Expected values: 2, 2, got: %d, %d", counts["a"], c.N)
+	}
+
+	// Failing update leaves typed values unchanged:
+	err := ApplyUpdate(v, decodeJSON(`{"$inc": {"counts.a": 1, "c.N": 1}, "$push": {"c.N": 1}}`))
+	if err == nil {
+		t.Errorf("Expected error")
+	}
+	if counts["a"] != 2 || c.N != 2 {
+		t.Errorf("Expected values: 2, 2, got: %d, %d", counts["a"], c.N)
+	}
+}
+
+func TestApplyUpdateOrdered(t *testing.T) {
+	m := mustOrdered(`{"a": 1, "b": 2, "c": "x"}`)
+
+	// Failing update restores deleted keys at their positions:
+	if err := ApplyUpdate(m, decodeJSON(`{"$unset": {"a": ""}, "$inc": {"c": 1}}`)); err == nil {
+		t.Errorf("Expected error")
+	}
+	if exp := []string{"a", "b", "c"}; !reflect.DeepEqual(m.Keys(), exp) {
+		t.Errorf("Expected keys: %v, got: %v", exp, m.Keys())
+	}
+	if a, _ := m.Get("a"); a != 1.0 {
+		t.Errorf("Expected value: %v, got: %v", 1, a)
+	}
+}
@@ -0,0 +1,456 @@
+package dyno
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Wildcard is the type of the wildcard path elements Any and AnyDeep.
+type Wildcard int
+
+const (
+	// Any is a wildcard path element that matches all keys of a map and
+	// all indices of a slice.
+	Any Wildcard = iota + 1
+
+	// AnyDeep is a wildcard path element that matches any number of path
+	// elements (including zero), that is, recursive descent into all maps
+	// and slices.
+	AnyDeep
+)
+
+// String returns the name of the wildcard.
+func (w Wildcard) String() string {
+	switch w {
+	case Any:
+		return "Any"
+	case AnyDeep:
+		return "AnyDeep"
+	}
+	return fmt.Sprintf("Wildcard(%d)", int(w))
+}
+
+// GetAll returns all values denoted by the path which may contain the
+// wildcards Any and AnyDeep, along with the concrete paths of the values.
+//
+// Path elements that do not match (e.g. missing map keys, invalid slice
+// indices or non-map and non-slice nodes) are skipped, they are not errors.
+// Map keys are visited in sorted order, so the result is deterministic.
+//
+// If path is empty or nil, v is returned.
+func GetAll(v interface{}, path ...interface{}) (values []interface{}, paths [][]interface{}) {
+	if len(path) == 0 {
+		return []interface{}{v}, [][]interface{}{{}}
+	}
+
+	walkPattern(v, path, nil, false, walkedPtrs{}, func(parent, key, value interface{}, exists bool, p []interface{}) (bool, error) {
+		values = append(values, value)
+		paths = append(paths, p)
+		return false, nil
+	})
+	return
+}
+
+// SetAll sets all map or slice elements denoted by the path which may
+// contain the wildcards Any and AnyDeep. The number of set elements and
+// their concrete paths are returned.
+//
+// If the last path element is a map key, it is set in all matching maps,
+// even if it does not exist yet (just like Set does). Path elements that
+// do not match are skipped. The last path element cannot be AnyDeep.
+//
+// Path cannot be empty or nil, else an error is returned.
+func SetAll(v interface{}, value interface{}, path ...interface{}) (n int, paths [][]interface{}, err error) {
+	return UpdateAll(v, func(old interface{}, exists bool) (interface{}, error) {
+		return value, nil
+	}, path...)
+}
+
+// UpdateAll applies fn to all values denoted by the path which may contain
+// the wildcards Any and AnyDeep, and sets the values returned by fn.
+// The number of updated elements and their concrete paths are returned.
+//
+// fn receives the current value and whether it exists (see Update).
+// If fn returns an error, UpdateAll stops and returns the error; elements
+// updated before that remain updated.
+//
+// Matching rules are the same as of SetAll.
+//
+// Path cannot be empty or nil, else an error is returned.
+func UpdateAll(v interface{}, fn func(old interface{}, exists bool) (interface{}, error), path ...interface{}) (n int, paths [][]interface{}, err error) {
+	if err = checkPattern(path); err != nil {
+		return
+	}
+
+	_, err = walkPattern(v, path, nil, true, walkedPtrs{}, func(parent, key, value interface{}, exists bool, p []interface{}) (bool, error) {
+		newValue, err := fn(value, exists)
+		if err != nil {
+			return false, fmt.Errorf("failed to update %v: %v", p, err)
+		}
+		if err := setElem(parent, key, len(p)-1, newValue); err != nil {
+			return false, err
+		}
+		paths = append(paths, p)
+		return false, nil
+	})
+	return len(paths), paths, err
+}
+
+// DeleteAll deletes all map or slice elements denoted by the path which
+// may contain the wildcards Any and AnyDeep. The number of deleted
+// elements and their concrete paths are returned.
+//
+// Unlike Delete, the key or index to delete is the last element of the path.
+// Path elements that do not match are skipped. The last path element cannot
+// be AnyDeep. Deleting elements of v itself is not allowed if v is a slice.
+//
+// Path cannot be empty or nil, else an error is returned.
+func DeleteAll(v interface{}, path ...interface{}) (n int, paths [][]interface{}, err error) {
+	if err = checkPattern(path); err != nil {
+		return
+	}
+
+	_, err = walkPattern(v, path, nil, false, walkedPtrs{}, func(parent, key, value interface{}, exists bool, p []interface{}) (bool, error) {
+		if reflect.ValueOf(parent).Kind() == reflect.Slice && len(p) == 1 {
+			return false, fmt.Errorf("cannot delete elements of v if v is a slice")
+		}
+		paths = append(paths, p)
+		return true, nil
+	})
+	return len(paths), paths, err
+}
+
+// checkPattern checks if path is a valid pattern for the modifying
+// functions.
+func checkPattern(path []interface{}) error {
+	if len(path) == 0 {
+		return fmt.Errorf("path cannot be empty")
+	}
+	if path[len(path)-1] == AnyDeep {
+		return fmt.Errorf("last path element cannot be AnyDeep")
+	}
+	return nil
+}
+
+// matchFunc is called for each element matched by a pattern path.
+// parent is the map or slice holding the element, key is the map key or
+// slice index, path is the concrete path of the element.
+// The first return value tells if the element is to be deleted.
+type matchFunc func(parent, key, value interface{}, exists bool, path []interface{}) (bool, error)
+
+// walkPattern walks node, calling f for each element matched by the
+// pattern path. prefix is the concrete path of node.
+//
+// If create is true, a map key being the last path element matches even if
+// it does not exist.
+//
+// Deleting elements of a slice creates a new slice, so the returned node
+// must be stored in place of node.
+//
+// seen holds the struct pointers being walked (see walkChildren).
+func walkPattern(node interface{}, path, prefix []interface{}, create bool, seen walkedPtrs, f matchFunc) (interface{}, error) {
+	el := path[0]
+
+	if el == AnyDeep {
+		rest := path[1:]
+		for len(rest) > 0 && rest[0] == AnyDeep {
+			rest = rest[1:]
+		}
+
+		if len(rest) == 0 {
+			// Matches node itself and all its descendants:
+			if _, err := f(nil, nil, node, true, copyPath(prefix)); err != nil {
+				return node, err
+			}
+			return node, walkChildren(node, prefix, seen, func(key, child interface{}, p []interface{}) (interface{}, error) {
+				return walkPattern(child, path, p, create, seen, f)
+			})
+		}
+
+		// Zero levels:
+		node, err := walkPattern(node, rest, prefix, create, seen, f)
+		if err != nil {
+			return node, err
+		}
+		// One or more levels:
+		return node, walkChildren(node, prefix, seen, func(key, child interface{}, p []interface{}) (interface{}, error) {
+			return walkPattern(child, path, p, create, seen, f)
+		})
+	}
+
+	if len(path) > 1 {
+		return node, walkMatching(node, el, prefix, false, seen, func(key, child interface{}, exists bool, p []interface{}) error {
+			newChild, err := walkPattern(child, path[1:], p, create, seen, f)
+			if err == nil && replaced(child, newChild) {
+				err = setElem(node, key, len(prefix), newChild)
+			}
+			return err
+		})
+	}
+
+	// Last path element:
+	var dels []int          // Slice indices to delete
+	var cdels []interface{} // Container keys to delete
+	err := walkMatching(node, el, prefix, create, seen, func(key, child interface{}, exists bool, p []interface{}) error {
+		del, err := f(node, key, child, exists, p)
+		if del {
+			switch n := node.(type) {
+			case map[string]interface{}:
+				delete(n, key.(string))
+			case map[interface{}]interface{}:
+				delete(n, key)
+			case []interface{}:
+				dels = append(dels, key.(int))
+			case Container:
+				cdels = append(cdels, key)
+			default:
+				rv, ok := reflectNode(n)
+				switch {
+				case ok && rv.Kind() == reflect.Map:
+					k, kerr := reflectKey(rv, key, len(p)-1)
+					if kerr != nil {
+						return kerr
+					}
+					rv.SetMapIndex(k, reflect.Value{})
+				case ok && rv.Kind() == reflect.Slice:
+					dels = append(dels, key.(int))
+				default:
+					return fmt.Errorf("cannot delete element of %T", n)
+				}
+			}
+		}
+		return err
+	})
+	if len(cdels) > 0 && err == nil {
+		err = deleteElems(node.(Container), cdels)
+	}
+	if len(dels) == 0 {
+		return node, err
+	}
+
+	s, ok := node.([]interface{})
+	if !ok {
+		return reflectDeleteIndices(node, dels), err
+	}
+	res := s[:0]
+	for i, el := range s {
+		if len(dels) > 0 && dels[0] == i {
+			dels = dels[1:]
+			continue
+		}
+		res = append(res, el)
+	}
+	// Clear the emptied elements:
+	for i := len(res); i < len(s); i++ {
+		s[i] = nil
+	}
+	return res, err
+}
+
+// walkMatching calls f for each child of node matched by the (non-AnyDeep)
+// path element el. prefix is the concrete path of node.
+//
+// If create is true, a missing map key also matches.
+func walkMatching(node interface{}, el interface{}, prefix []interface{}, create bool, seen walkedPtrs,
+	f func(key, child interface{}, exists bool, path []interface{}) error) error {
+
+	if el == Any {
+		return walkChildren(node, prefix, seen, func(key, child interface{}, p []interface{}) (interface{}, error) {
+			return child, f(key, child, true, p)
+		})
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if _, ok := el.(string); !ok {
+			return nil
+		}
+	case map[interface{}]interface{}:
+	case []interface{}:
+		idx, ok := el.(int)
+		if !ok || idx < 0 || idx >= len(n) {
+			return nil
+		}
+	case Container:
+	default:
+		if _, ok := reflectNode(node); !ok {
+			return nil
+		}
+	}
+
+	child, exists, err := getElem(node, el, len(prefix))
+	if err != nil || !exists && !create {
+		return nil
+	}
+	return f(el, child, exists, appendPath(prefix, el))
+}
+
+// walkChildren calls f for each child of node (map keys in sorted order).
+// f returns the new value of the child which is stored if the child is a
+// slice that was replaced. prefix is the concrete path of node.
+//
+// seen holds the struct pointers being walked by the enclosing calls: these
+// are not walked again, so self-referencing structs do not cause infinite
+// recursion.
+func walkChildren(node interface{}, prefix []interface{}, seen walkedPtrs,
+	f func(key, child interface{}, path []interface{}) (interface{}, error)) error {
+
+	visit := func(key, child interface{}) error {
+		newChild, err := f(key, child, appendPath(prefix, key))
+		if err == nil && replaced(child, newChild) {
+			err = setElem(node, key, len(prefix), newChild)
+		}
+		return err
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeysS(n) {
+			if err := visit(k, n[k]); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		for _, k := range sortedKeysI(n) {
+			if err := visit(k, n[k]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, child := range n {
+			if err := visit(i, child); err != nil {
+				return err
+			}
+		}
+	case Container:
+		return containerChildren(n, visit)
+	default:
+		if rv, ok := reflectNode(node); ok {
+			if rv.Kind() == reflect.Struct {
+				p, ok := structPtr(node)
+				if ok {
+					if seen[p] {
+						return nil
+					}
+					seen[p] = true
+					defer delete(seen, p)
+				}
+			}
+			return reflectChildren(rv, visit)
+		}
+	}
+
+	return nil
+}
+
+// walkedPtrs is a set of the struct pointers being walked by walkChildren.
+type walkedPtrs map[uintptr]bool
+
+// structPtr returns the address held by node if node is a pointer.
+func structPtr(node interface{}) (uintptr, bool) {
+	rv := reflect.ValueOf(node)
+	if rv.Kind() != reflect.Ptr {
+		return 0, false
+	}
+	return rv.Pointer(), true
+}
+
+// reflectDeleteIndices deletes the elements at the (ascending) indices dels
+// of the reflected slice node. The slice is updated in place if it is
+// addressable (it was reached through a pointer), else the new slice is
+// returned.
+func reflectDeleteIndices(node interface{}, dels []int) interface{} {
+	rv, _ := reflectNode(node)
+	n := 0
+	for i := 0; i < rv.Len(); i++ {
+		if len(dels) > 0 && dels[0] == i {
+			dels = dels[1:]
+			continue
+		}
+		rv.Index(n).Set(rv.Index(i))
+		n++
+	}
+	// Clear the emptied elements:
+	for i := n; i < rv.Len(); i++ {
+		rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+	}
+	if rv.CanSet() {
+		rv.SetLen(n)
+		return node
+	}
+	return rv.Slice(0, n).Interface()
+}
+
+// replaced tells if newNode is a slice that replaced the old node
+// (because elements were deleted from it).
+func replaced(old, newNode interface{}) bool {
+	if s, ok := newNode.([]interface{}); ok {
+		s2, ok := old.([]interface{})
+		return !ok || len(s) != len(s2)
+	}
+	rv := reflect.ValueOf(newNode)
+	if rv.Kind() != reflect.Slice {
+		return false
+	}
+	rv2 := reflect.ValueOf(old)
+	return rv2.Kind() != reflect.Slice || rv.Len() != rv2.Len()
+}
+
+// appendPath returns a new path being path extended with el.
+func appendPath(path []interface{}, el interface{}) []interface{} {
+	p := make([]interface{}, len(path)+1)
+	copy(p, path)
+	p[len(path)] = el
+	return p
+}
+
+// copyPath returns a copy of path.
+func copyPath(path []interface{}) []interface{} {
+	p := make([]interface{}, len(path))
+	copy(p, path)
+	return p
+}
+
+// sortedKeysS returns the keys of m in sorted order.
+func sortedKeysS(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedKeysI returns the keys of m in a deterministic order: numbers are
+// ordered by their values, strings lexically, and keys of different types
+// by their type names.
+func sortedKeysI(m map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+	return keys
+}
+
+// lessKey reports whether the map key a sorts before the map key b.
+func lessKey(a, b interface{}) bool {
+	if isNumber(a) && isNumber(b) {
+		if c, ok := compareNumbers(a, b); ok && c != 0 {
+			return c < 0
+		}
+	}
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			return sa < sb
+		}
+	}
+	ta, tb := fmt.Sprintf("%T", a), fmt.Sprintf("%T", b)
+	if ta != tb {
+		return ta < tb
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
@@ -0,0 +1,232 @@
+package dyno
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newPlugins returns a fresh dynamic object for wildcard tests.
+func newPlugins() map[string]interface{} {
+	return map[string]interface{}{
+		"plugins": []interface{}{
+			map[string]interface{}{"name": "a", "enabled": true, "debug": 1},
+			map[interface{}]interface{}{"name": "b", "debug": 2},
+			"c",
+		},
+		"services": map[string]interface{}{
+			"db":  map[string]interface{}{"debug": true, "port": 5432},
+			"web": map[string]interface{}{"port": 80},
+		},
+		"debug": false,
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	cases := []struct {
+		title  string          // Title of the test case
+		v      interface{}     // Input dynamic object
+		path   []interface{}   // path pattern
+		values []interface{}   // Expected values
+		paths  [][]interface{} // Expected concrete paths
+	}{
+		{
+			title:  "empty path",
+			v:      1,
+			values: []interface{}{1},
+			paths:  [][]interface{}{{}},
+		},
+		{
+			title:  "concrete path",
+			v:      newPlugins(),
+			path:   []interface{}{"services", "web", "port"},
+			values: []interface{}{80},
+			paths:  [][]interface{}{{"services", "web", "port"}},
+		},
+		{
+			title:  "Any over slice",
+			v:      newPlugins(),
+			path:   []interface{}{"plugins", Any, "name"},
+			values: []interface{}{"a", "b"},
+			paths:  [][]interface{}{{"plugins", 0, "name"}, {"plugins", 1, "name"}},
+		},
+		{
+			title:  "Any over map",
+			v:      newPlugins(),
+			path:   []interface{}{"services", Any, "port"},
+			values: []interface{}{5432, 80},
+			paths:  [][]interface{}{{"services", "db", "port"}, {"services", "web", "port"}},
+		},
+		{
+			title:  "AnyDeep",
+			v:      newPlugins(),
+			path:   []interface{}{AnyDeep, "debug"},
+			values: []interface{}{false, 1, 2, true},
+			paths: [][]interface{}{
+				{"debug"},
+				{"plugins", 0, "debug"},
+				{"plugins", 1, "debug"},
+				{"services", "db", "debug"},
+			},
+		},
+		{
+			title:  "AnyDeep as last element",
+			v:      map[string]interface{}{"a": []interface{}{1}},
+			path:   []interface{}{AnyDeep},
+			values: []interface{}{map[string]interface{}{"a": []interface{}{1}}, []interface{}{1}, 1},
+			paths:  [][]interface{}{{}, {"a"}, {"a", 0}},
+		},
+		{
+			title: "no match",
+			v:     newPlugins(),
+			path:  []interface{}{"plugins", Any, "x"},
+		},
+	}
+
+	for _, c := range cases {
+		values, paths := GetAll(c.v, c.path...)
+		if !reflect.DeepEqual(values, c.values) {
+			t.Errorf("[title: %s] Expected values: %v, got: %v", c.title, c.values, values)
+		}
+		if !reflect.DeepEqual(paths, c.paths) {
+			t.Errorf("[title: %s] Expected paths: %v, got: %v", c.title, c.paths, paths)
+		}
+	}
+}
+
+func TestSetAll(t *testing.T) {
+	v := newPlugins()
+	n, paths, err := SetAll(v, false, "plugins", Any, "enabled")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expPaths := [][]interface{}{{"plugins", 0, "enabled"}, {"plugins", 1, "enabled"}}
+	if n != 2 || !reflect.DeepEqual(paths, expPaths) {
+		t.Errorf("Expected n: 2, paths: %v, got: %d, %v", expPaths, n, paths)
+	}
+	values, _ := GetAll(v, "plugins", Any, "enabled")
+	if exp := []interface{}{false, false}; !reflect.DeepEqual(values, exp) {
+		t.Errorf("Expected values: %v, got: %v", exp, values)
+	}
+
+	if _, _, err := SetAll(v, 1); err == nil {
+		t.Errorf("Expected error for empty path")
+	}
+	if _, _, err := SetAll(v, 1, "a", AnyDeep); err == nil {
+		t.Errorf("Expected error for AnyDeep last path element")
+	}
+}
+
+func TestUpdateAll(t *testing.T) {
+	v := newPlugins()
+	n, _, err := UpdateAll(v, func(old interface{}, exists bool) (interface{}, error) {
+		i, err := GetInteger(old)
+		return i + 1, err
+	}, "services", Any, "port")
+	if err != nil || n != 2 {
+		t.Errorf("Expected n: 2, got: %d, err value: %v", n, err)
+	}
+	if exp := []interface{}{int64(5433), int64(81)}; !reflect.DeepEqual(mustGetAll(v, "services", Any, "port"), exp) {
+		t.Errorf("Expected values: %v, got: %v", exp, mustGetAll(v, "services", Any, "port"))
+	}
+
+	// fn error:
+	_, _, err = UpdateAll(v, func(old interface{}, exists bool) (interface{}, error) {
+		_, err := GetInteger(old)
+		return old, err
+	}, "plugins", Any)
+	if err == nil {
+		t.Errorf("Expected error from fn")
+	}
+}
+
+func mustGetAll(v interface{}, path ...interface{}) []interface{} {
+	values, _ := GetAll(v, path...)
+	return values
+}
+
+func TestDeleteAll(t *testing.T) {
+	cases := []struct {
+		title string          // Title of the test case
+		v     interface{}     // Input dynamic object
+		path  []interface{}   // path pattern
+		exp   interface{}     // Expected result
+		paths [][]interface{} // Expected concrete paths
+		isErr bool            // Tells if error is expected
+	}{
+		// Test success:
+		{
+			title: "Any over map",
+			v: map[string]interface{}{
+				"services": map[string]interface{}{
+					"db":  map[string]interface{}{"debug": true, "port": 5432},
+					"web": map[interface{}]interface{}{"debug": false},
+				},
+			},
+			path: []interface{}{"services", Any, "debug"},
+			exp: map[string]interface{}{
+				"services": map[string]interface{}{
+					"db":  map[string]interface{}{"port": 5432},
+					"web": map[interface{}]interface{}{},
+				},
+			},
+			paths: [][]interface{}{{"services", "db", "debug"}, {"services", "web", "debug"}},
+		},
+		{
+			title: "slice elements",
+			v:     map[string]interface{}{"a": []interface{}{1, 2, 3}},
+			path:  []interface{}{"a", Any},
+			exp:   map[string]interface{}{"a": []interface{}{}},
+			paths: [][]interface{}{{"a", 0}, {"a", 1}, {"a", 2}},
+		},
+		{
+			title: "AnyDeep with nested slices",
+			v: map[string]interface{}{
+				"a": []interface{}{
+					[]interface{}{1, 2},
+					map[string]interface{}{"x": []interface{}{3, 4}},
+				},
+			},
+			path: []interface{}{AnyDeep, 0},
+			exp: map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"x": []interface{}{4}},
+				},
+			},
+			paths: [][]interface{}{{"a", 0}, {"a", 0, "x", 0}},
+		},
+		{
+			title: "no match",
+			v:     map[string]interface{}{"a": 1},
+			path:  []interface{}{"b", Any},
+			exp:   map[string]interface{}{"a": 1},
+		},
+
+		// Test errors:
+		{
+			title: "path cannot be empty error",
+			v:     map[string]interface{}{"a": 1},
+			exp:   map[string]interface{}{"a": 1},
+			isErr: true,
+		},
+		{
+			title: "root slice error",
+			v:     []interface{}{1, 2},
+			path:  []interface{}{Any},
+			exp:   []interface{}{1, 2},
+			isErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		n, paths, err := DeleteAll(c.v, c.path...)
+		if !reflect.DeepEqual(c.v, c.exp) {
+			t.Errorf("[title: %s] Expected value: %v, got: %v", c.title, c.exp, c.v)
+		}
+		if n != len(c.paths) || !reflect.DeepEqual(paths, c.paths) {
+			t.Errorf("[title: %s] Expected paths: %v, got: %d, %v", c.title, c.paths, n, paths)
+		}
+		if c.isErr != (err != nil) {
+			t.Errorf("[title: %s] Expected error: %v, got: %v, err value: %v", c.title, c.isErr, err != nil, err)
+		}
+	}
+}